@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"jfrog-assignment/internal/modules/downloader"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// fileConfig is the layout of the JSON configuration file passed with --config.
+// Values given on the command line take precedence over the file.
+type fileConfig struct {
+	HostLimits downloader.HostLimits     `json:"host_limits"` // Per-host rate limits and concurrency caps
+	HTTPClient clientFileConfig          `json:"http_client"` // Timeouts, connection pool and protocol settings
+	Headers    downloader.RequestHeaders `json:"headers"`     // Global and per-host request headers
+}
+
+// clientFileConfig is the "http_client" section of the configuration file.
+// Fields that are omitted keep the value of the corresponding flag.
+type clientFileConfig struct {
+	ConnectTimeout        *duration `json:"connect_timeout"`
+	TLSHandshakeTimeout   *duration `json:"tls_handshake_timeout"`
+	ResponseHeaderTimeout *duration `json:"response_header_timeout"`
+	Timeout               *duration `json:"timeout"`
+	MaxIdleConns          *int      `json:"max_idle_conns"`
+	MaxIdleConnsPerHost   *int      `json:"max_idle_conns_per_host"`
+	MaxConnsPerHost       *int      `json:"max_conns_per_host"`
+	IdleConnTimeout       *duration `json:"idle_conn_timeout"`
+	KeepAlive             *duration `json:"keep_alive"`
+	DisableKeepAlives     *bool     `json:"disable_keep_alives"`
+	DisableHTTP2          *bool     `json:"disable_http2"`
+
+	MaxRedirects           *int  `json:"max_redirects"`
+	DenyCrossHostRedirects *bool `json:"deny_cross_host_redirects"`
+	DenyHTTPSDowngrade     *bool `json:"deny_https_downgrade"`
+}
+
+// duration is a time.Duration read from a JSON string such as "30s".
+type duration time.Duration
+
+// UnmarshalJSON parses a duration string in the format accepted by time.ParseDuration.
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("expected a duration string such as \"30s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+// loadConfig reads the JSON configuration file at path.
+//
+// Parameters:
+//   - path: Path to the configuration file; an empty path yields the zero configuration.
+//
+// Returns:
+//   - The parsed configuration, or an error if the file cannot be read or contains unknown fields.
+func loadConfig(path string) (fileConfig, error) {
+	var cfg fileConfig
+	if path == "" {
+		return cfg, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return cfg, fmt.Errorf("open config: %w", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// resolveHostLimits merges the host limits from the configuration file with the command-line flags.
+//
+// Parameters:
+//   - cfg: The configuration loaded from file.
+//   - flags: The parsed command-line flags, used to detect which defaults were set explicitly.
+//
+// Returns:
+//   - The effective host limits, or an error if a --host-limit value is malformed.
+func resolveHostLimits(cfg fileConfig, flags *pflag.FlagSet) (downloader.HostLimits, error) {
+	limits := downloader.HostLimits{
+		Default: cfg.HostLimits.Default,
+		Hosts:   make(map[string]downloader.HostLimit),
+	}
+	for host, limit := range cfg.HostLimits.Hosts {
+		limits.Hosts[strings.ToLower(host)] = limit
+	}
+
+	if flags.Changed("host-rps") {
+		limits.Default.RequestsPerSecond = hostLimit.RequestsPerSecond
+	}
+	if flags.Changed("host-burst") {
+		limits.Default.Burst = hostLimit.Burst
+	}
+	if flags.Changed("host-concurrency") {
+		limits.Default.MaxConcurrency = hostLimit.MaxConcurrency
+	}
+
+	for _, spec := range hostLimitSpecs {
+		host, limit, err := parseHostLimit(spec)
+		if err != nil {
+			return limits, err
+		}
+		limits.Hosts[host] = limit
+	}
+	return limits, nil
+}
+
+// resolveClientConfig merges the HTTP client settings from the configuration file with the command-line flags.
+//
+// Parameters:
+//   - cfg: The configuration loaded from file.
+//   - flags: The parsed command-line flags, used to detect which defaults were set explicitly.
+//
+// Returns:
+//   - The effective client settings.
+func resolveClientConfig(cfg fileConfig, flags *pflag.FlagSet) downloader.ClientConfig {
+	resolved := clientConfig
+	file := cfg.HTTPClient
+
+	setDuration := func(flag string, dst *time.Duration, value *duration) {
+		if value != nil && !flags.Changed(flag) {
+			*dst = time.Duration(*value)
+		}
+	}
+	setInt := func(flag string, dst *int, value *int) {
+		if value != nil && !flags.Changed(flag) {
+			*dst = *value
+		}
+	}
+	setBool := func(flag string, dst *bool, value *bool) {
+		if value != nil && !flags.Changed(flag) {
+			*dst = *value
+		}
+	}
+
+	setDuration("connect-timeout", &resolved.ConnectTimeout, file.ConnectTimeout)
+	setDuration("tls-handshake-timeout", &resolved.TLSHandshakeTimeout, file.TLSHandshakeTimeout)
+	setDuration("response-header-timeout", &resolved.ResponseHeaderTimeout, file.ResponseHeaderTimeout)
+	setDuration("timeout", &resolved.Timeout, file.Timeout)
+	setInt("max-idle-conns", &resolved.MaxIdleConns, file.MaxIdleConns)
+	setInt("max-idle-conns-per-host", &resolved.MaxIdleConnsPerHost, file.MaxIdleConnsPerHost)
+	setInt("max-conns-per-host", &resolved.MaxConnsPerHost, file.MaxConnsPerHost)
+	setDuration("idle-conn-timeout", &resolved.IdleConnTimeout, file.IdleConnTimeout)
+	setDuration("keep-alive", &resolved.KeepAlive, file.KeepAlive)
+	setBool("disable-keep-alives", &resolved.DisableKeepAlives, file.DisableKeepAlives)
+	setBool("no-http2", &resolved.DisableHTTP2, file.DisableHTTP2)
+	setInt("max-redirects", &resolved.MaxRedirects, file.MaxRedirects)
+	setBool("no-cross-host-redirects", &resolved.DenyCrossHostRedirects, file.DenyCrossHostRedirects)
+	setBool("no-https-downgrade", &resolved.DenyHTTPSDowngrade, file.DenyHTTPSDowngrade)
+	return resolved
+}
+
+// resolveHeaders merges the request headers from the configuration file with the --header and --host-header flags.
+// References to environment variables such as ${API_TOKEN} in header values are expanded, so that secrets need not
+// be written to the file or appear in the process list.
+//
+// Parameters:
+//   - cfg: The configuration loaded from file.
+//
+// Returns:
+//   - The effective headers, or an error if a flag value is malformed or references an unset variable.
+func resolveHeaders(cfg fileConfig) (downloader.RequestHeaders, error) {
+	headers := downloader.RequestHeaders{
+		Global: make(map[string]string),
+		Hosts:  make(map[string]map[string]string),
+	}
+	set := func(dst map[string]string, name, value string) error {
+		expanded, err := expandEnv(value)
+		if err != nil {
+			return fmt.Errorf("header %s: %w", name, err)
+		}
+		dst[http.CanonicalHeaderKey(name)] = expanded
+		return nil
+	}
+	hostHeaders := func(host string) map[string]string {
+		host = strings.ToLower(host)
+		if headers.Hosts[host] == nil {
+			headers.Hosts[host] = make(map[string]string)
+		}
+		return headers.Hosts[host]
+	}
+
+	for name, value := range cfg.Headers.Global {
+		if err := set(headers.Global, name, value); err != nil {
+			return headers, err
+		}
+	}
+	for host, values := range cfg.Headers.Hosts {
+		for name, value := range values {
+			if err := set(hostHeaders(host), name, value); err != nil {
+				return headers, err
+			}
+		}
+	}
+
+	for _, spec := range headerSpecs {
+		name, value, ok := downloader.ParseHeader(spec)
+		if !ok {
+			return headers, fmt.Errorf("invalid --header %q: expected \"Name: value\"", spec)
+		}
+		if err := set(headers.Global, name, value); err != nil {
+			return headers, err
+		}
+	}
+	for _, spec := range hostHeaderSpecs {
+		host, header, _ := strings.Cut(spec, ",")
+		name, value, ok := downloader.ParseHeader(header)
+		if strings.TrimSpace(host) == "" || !ok {
+			return headers, fmt.Errorf("invalid --host-header %q: expected \"host,Name: value\"", spec)
+		}
+		if err := set(hostHeaders(strings.TrimSpace(host)), name, value); err != nil {
+			return headers, err
+		}
+	}
+	return headers, nil
+}
+
+// expandEnv replaces ${VAR} and $VAR in value with the value of the environment variable.
+//
+// Returns:
+//   - The expanded value, or an error naming the first variable that is not set.
+func expandEnv(value string) (string, error) {
+	var missing string
+	expanded := os.Expand(value, func(name string) string {
+		v, ok := os.LookupEnv(name)
+		if !ok && missing == "" {
+			missing = name
+		}
+		return v
+	})
+	if missing != "" {
+		return "", fmt.Errorf("environment variable %s is not set", missing)
+	}
+	return expanded, nil
+}
+
+// parseHostLimit parses a --host-limit value of the form "host,rps=5,burst=10,concurrency=2".
+// Keys that are omitted are unlimited.
+//
+// Parameters:
+//   - spec: The flag value.
+//
+// Returns:
+//   - The lowercased host, its limit, and an error if the value is malformed.
+func parseHostLimit(spec string) (string, downloader.HostLimit, error) {
+	var limit downloader.HostLimit
+
+	parts := strings.Split(spec, ",")
+	host := strings.ToLower(strings.TrimSpace(parts[0]))
+	if host == "" {
+		return "", limit, fmt.Errorf("invalid --host-limit %q: missing host", spec)
+	}
+
+	for _, part := range parts[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return "", limit, fmt.Errorf("invalid --host-limit %q: expected key=value, got %q", spec, part)
+		}
+		var err error
+		switch key {
+		case "rps":
+			limit.RequestsPerSecond, err = strconv.ParseFloat(value, 64)
+		case "burst":
+			limit.Burst, err = strconv.Atoi(value)
+		case "concurrency":
+			limit.MaxConcurrency, err = strconv.Atoi(value)
+		default:
+			err = fmt.Errorf("unknown key %q", key)
+		}
+		if err != nil {
+			return "", limit, fmt.Errorf("invalid --host-limit %q: %w", spec, err)
+		}
+	}
+	return host, limit, nil
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"jfrog-assignment/internal/modules/downloader"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func TestParseHostLimit(t *testing.T) {
+	tests := []struct {
+		spec      string
+		host      string
+		rps       float64
+		burst     int
+		conc      int
+		expectErr bool
+	}{
+		{spec: "Artifacts.Internal,rps=5,burst=10,concurrency=2", host: "artifacts.internal", rps: 5, burst: 10, conc: 2},
+		{spec: "mirror.example:8080,concurrency=4", host: "mirror.example:8080", conc: 4},
+		{spec: ",rps=5", expectErr: true},
+		{spec: "host,rps", expectErr: true},
+		{spec: "host,speed=5", expectErr: true},
+		{spec: "host,rps=fast", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			host, limit, err := parseHostLimit(tt.spec)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if host != tt.host || limit.RequestsPerSecond != tt.rps || limit.Burst != tt.burst || limit.MaxConcurrency != tt.conc {
+				t.Errorf("unexpected result %q %+v", host, limit)
+			}
+		})
+	}
+}
+
+func TestResolveHostLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{"host_limits": {"default": {"requests_per_second": 10, "max_concurrency": 8}, "hosts": {"A.example": {"max_concurrency": 1}}}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.IntVar(&hostLimit.MaxConcurrency, "host-concurrency", 0, "")
+	if err := flags.Parse([]string{"--host-concurrency", "4"}); err != nil {
+		t.Fatal(err)
+	}
+	hostLimitSpecs = []string{"b.example,rps=2"}
+	defer func() { hostLimitSpecs = nil }()
+
+	limits, err := resolveHostLimits(cfg, flags)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limits.Default.RequestsPerSecond != 10 || limits.Default.MaxConcurrency != 4 {
+		t.Errorf("expected file rps and flag concurrency, got %+v", limits.Default)
+	}
+	if limits.Hosts["a.example"].MaxConcurrency != 1 {
+		t.Errorf("expected override from file for a.example, got %+v", limits.Hosts)
+	}
+	if limits.Hosts["b.example"].RequestsPerSecond != 2 {
+		t.Errorf("expected override from flag for b.example, got %+v", limits.Hosts)
+	}
+}
+
+func TestResolveClientConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := `{"http_client": {"connect_timeout": "5s", "timeout": "10m", "max_conns_per_host": 4, "disable_http2": true, "deny_https_downgrade": true}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defaults := clientConfig
+	defer func() { clientConfig = defaults }()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.DurationVar(&clientConfig.Timeout, "timeout", clientConfig.Timeout, "")
+	if err := flags.Parse([]string{"--timeout", "1m"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved := resolveClientConfig(cfg, flags)
+	if resolved.ConnectTimeout != 5*time.Second || resolved.MaxConnsPerHost != 4 || !resolved.DisableHTTP2 ||
+		!resolved.DenyHTTPSDowngrade {
+		t.Errorf("expected settings from file, got %+v", resolved)
+	}
+	if resolved.Timeout != time.Minute {
+		t.Errorf("expected flag to override file timeout, got %v", resolved.Timeout)
+	}
+	if resolved.ResponseHeaderTimeout != defaults.ResponseHeaderTimeout {
+		t.Errorf("expected default response header timeout, got %v", resolved.ResponseHeaderTimeout)
+	}
+}
+
+func TestLoadConfig_InvalidDuration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"http_client": {"timeout": 30}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(path); err == nil {
+		t.Errorf("expected error for numeric duration, got nil")
+	}
+}
+
+func TestResolveHeaders(t *testing.T) {
+	t.Setenv("TEST_API_TOKEN", "s3cret")
+	cfg := fileConfig{Headers: downloader.RequestHeaders{
+		Global: map[string]string{"x-team": "core"},
+		Hosts:  map[string]map[string]string{"Artifacts.Internal": {"Authorization": "Bearer ${TEST_API_TOKEN}"}},
+	}}
+	headerSpecs = []string{"X-Trace: 1"}
+	hostHeaderSpecs = []string{"mirror.example,X-Api-Key: $TEST_API_TOKEN"}
+	defer func() { headerSpecs, hostHeaderSpecs = nil, nil }()
+
+	headers, err := resolveHeaders(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if headers.Global["X-Team"] != "core" || headers.Global["X-Trace"] != "1" {
+		t.Errorf("unexpected global headers %v", headers.Global)
+	}
+	if headers.Hosts["artifacts.internal"]["Authorization"] != "Bearer s3cret" {
+		t.Errorf("expected expanded token for artifacts.internal, got %v", headers.Hosts)
+	}
+	if headers.Hosts["mirror.example"]["X-Api-Key"] != "s3cret" {
+		t.Errorf("expected expanded token for mirror.example, got %v", headers.Hosts)
+	}
+}
+
+func TestResolveHeaders_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   fileConfig
+		specs []string
+		hosts []string
+	}{
+		{name: "unset variable", cfg: fileConfig{Headers: downloader.RequestHeaders{Global: map[string]string{"Authorization": "Bearer ${TEST_UNSET_TOKEN}"}}}},
+		{name: "malformed header", specs: []string{"X-Trace"}},
+		{name: "missing host", hosts: []string{"X-Api-Key: 1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headerSpecs, hostHeaderSpecs = tt.specs, tt.hosts
+			defer func() { headerSpecs, hostHeaderSpecs = nil, nil }()
+			if _, err := resolveHeaders(tt.cfg); err == nil {
+				t.Errorf("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadConfig_UnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"host_limit": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(path); err == nil {
+		t.Errorf("expected error for unknown field, got nil")
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"jfrog-assignment/internal/modules/crawler"
+	"jfrog-assignment/internal/modules/pipeline"
+	"jfrog-assignment/internal/modules/webreader"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	maxDepth   int    // Links followed from a seed URL, set via command-line flag
+	maxPages   int    // URLs downloaded per crawl (0 = unlimited), set via command-line flag
+	crawlScope string // Which discovered URLs are followed ("host" or "prefix"), set via command-line flag
+)
+
+var crawlCmd = &cobra.Command{
+	Use:   "crawl [seed URL...]",
+	Short: "Download the pages reachable from seed URLs by following their links",
+	Long: `Download the seed URLs, then every URL linked from the downloaded HTML pages and stylesheets (a, link,
+img, script and similar elements, and CSS url() references), shallowest first, until --max-depth or --max-pages
+is reached. Each URL is downloaded once, even when a shorter path to it turns up later (its links are then followed
+again from the lower depth), and only URLs within --scope and passing --include/--exclude are followed`,
+	Args: cobra.MinimumNArgs(1),
+}
+
+// init registers the crawl command and its flags.
+func init() {
+	crawlCmd.Flags().IntVar(&maxDepth, "max-depth", crawler.DefaultMaxDepth, "Links followed from a seed URL (0 only downloads the seeds)")
+	crawlCmd.Flags().IntVar(&maxPages, "max-pages", crawler.DefaultMaxPages, "Maximum URLs downloaded, seeds included (0 = unlimited)")
+	crawlCmd.Flags().StringVar(&crawlScope, "scope", string(crawler.ScopeHost), "URLs followed: host (on the host of a seed) or prefix (below the directory of a seed)")
+	rootCmd.AddCommand(crawlCmd)
+}
+
+// runCrawl crawls from the given seed URLs, downloading and saving every page found like the root command does.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - logger: Logger for logging progress and errors.
+//   - seeds: URLs the crawl starts from.
+//
+// Returns:
+//   - An error if the flags or a seed are invalid or a pipeline stage failed, nil otherwise (including on cancellation).
+func runCrawl(ctx context.Context, logger *zap.Logger, seeds []string) error {
+	c, err := newCrawler(seeds)
+	if err != nil {
+		return err
+	}
+	setup, err := setupDownloads(logger)
+	if err != nil {
+		return err
+	}
+	defer setup.close()
+
+	urls := pipeline.NewChain(logger, c)
+	contents := pipeline.Then(urls, setup.downloader)
+	pages := pipeline.Then(contents, c.Extractor())
+	return runPipeline(ctx, logger, pipeline.Then(pages, setup.persister), setup.policy)
+}
+
+// newCrawler creates the Crawler described by the crawl command-line flags.
+//
+// Parameters:
+//   - seeds: URLs the crawl starts from.
+//
+// Returns:
+//   - The Crawler, or an error if the scope, a filter pattern or a seed is invalid.
+func newCrawler(seeds []string) (*crawler.Crawler, error) {
+	scope, err := crawler.ParseScope(crawlScope)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := webreader.NewFilter(includePatterns, excludePatterns)
+	if err != nil {
+		return nil, err
+	}
+	opts := []crawler.Option{
+		crawler.WithMaxDepth(maxDepth),
+		crawler.WithMaxPages(maxPages),
+		crawler.WithScope(scope),
+		crawler.WithFilter(filter),
+	}
+	if preferHTTPS {
+		opts = append(opts, crawler.WithPreferHTTPS())
+	}
+	return crawler.New(seeds, opts...)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNewCrawler(t *testing.T) {
+	defer func(scope string, include []string) { crawlScope, includePatterns = scope, include }(crawlScope, includePatterns)
+
+	tests := []struct {
+		name      string
+		seeds     []string
+		scope     string
+		include   []string
+		expectErr bool
+	}{
+		{name: "host scope", seeds: []string{"https://example.com/"}, scope: "host"},
+		{name: "prefix scope", seeds: []string{"example.com/docs/"}, scope: "prefix", include: []string{`\.html$`}},
+		{name: "unknown scope", seeds: []string{"https://example.com/"}, scope: "domain", expectErr: true},
+		{name: "invalid pattern", seeds: []string{"https://example.com/"}, scope: "host", include: []string{"("}, expectErr: true},
+		{name: "invalid seed", seeds: []string{"ftp://example.com/"}, scope: "host", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		crawlScope, includePatterns = tt.scope, tt.include
+		if _, err := newCrawler(tt.seeds); tt.expectErr != (err != nil) {
+			t.Errorf("%s: newCrawler() error = %v, expected error %v", tt.name, err, tt.expectErr)
+		}
+	}
+}
+
+func TestRunCrawl(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<a href="/a.html">A</a> <a href="/b.html">B</a>`))
+		case "/a.html":
+			w.Write([]byte(`<a href="/">home</a> <a href="/b.html">B</a>`))
+		case "/b.html":
+			w.Write([]byte(`<a href="/c.html">C</a>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	defer func(depth, pages int, scope, policy string) {
+		maxDepth, maxPages, crawlScope, errorPolicy = depth, pages, scope, policy
+	}(maxDepth, maxPages, crawlScope, errorPolicy)
+	maxDepth, maxPages, crawlScope, errorPolicy = 1, 0, "host", "fail-fast"
+
+	if err := runCrawl(context.Background(), logger, []string{ts.URL + "/"}); err != nil {
+		t.Fatalf("runCrawl() error = %v", err)
+	}
+	// The seed and the two pages it links to; c.html is beyond --max-depth.
+	entries, err := os.ReadDir("downloads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("expected 3 downloaded files, got %d", len(entries))
+	}
+}
+
+func TestCrawlInheritsFlags(t *testing.T) {
+	for _, name := range []string{"output", "retries", "include", "prefer-https", "manifest"} {
+		if crawlCmd.InheritedFlags().Lookup(name) == nil {
+			t.Errorf("crawl does not accept --%s", name)
+		}
+	}
+	for _, name := range []string{"csv", "sitemap"} {
+		if crawlCmd.InheritedFlags().Lookup(name) != nil {
+			t.Errorf("crawl accepts input flag --%s", name)
+		}
+	}
+
+	// Settings merged from --config must see flags given after the subcommand.
+	defaults := clientConfig
+	timeout := rootCmd.PersistentFlags().Lookup("timeout")
+	defer func() { clientConfig, timeout.Changed = defaults, false }()
+	if err := crawlCmd.ParseFlags([]string{"--timeout", "1m"}); err != nil {
+		t.Fatal(err)
+	}
+	if !rootCmd.PersistentFlags().Changed("timeout") {
+		t.Error("--timeout given to crawl is not seen as changed")
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"jfrog-assignment/internal/modules/persistence"
+	"jfrog-assignment/internal/modules/storage"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	gcDryRun bool     // Whether gc only reports what it would remove, set via command-line flag
+	gcForget []string // Keys of CAS entries to drop before collecting, set via command-line flags
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove content-addressed blobs no longer referenced by any downloaded file",
+	Long: `Prune the content-addressable store written with --cas: entries whose file was deleted or replaced, and
+entries given with --forget, are dropped from the index, and blobs that are no longer referenced are removed`,
+	Args: cobra.NoArgs,
+}
+
+// init registers the gc command and its flags.
+func init() {
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "Only report what would be removed")
+	gcCmd.Flags().StringArrayVar(&gcForget, "forget", nil, "Drop the entry saved under this path, relative to --output, e.g. an index entry (repeatable)")
+	rootCmd.AddCommand(gcCmd)
+}
+
+// runGC garbage-collects the content-addressable store in the --output directory.
+//
+// Parameters:
+//   - logger: Logger for reporting what was removed.
+//
+// Returns:
+//   - An error if the configuration file is invalid, --output is not local, or the store cannot be opened or
+//     collected, nil otherwise.
+func runGC(logger *zap.Logger) error {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	out, err := openStorage(resolveClientConfig(cfg, rootCmd.PersistentFlags()))
+	if err != nil {
+		return err
+	}
+	local, ok := out.(*storage.Local)
+	if !ok {
+		return fmt.Errorf("gc requires a local --output, got %q", output)
+	}
+	store, err := persistence.OpenCAS(local.Root(), persistence.LinkHardlink)
+	if err != nil {
+		return err
+	}
+	forgotten := 0
+	for _, key := range gcForget {
+		if store.Forget(filepath.ToSlash(filepath.Clean(key))) {
+			forgotten++
+		} else {
+			logger.Warn("no CAS entry to forget", zap.String("path", key))
+		}
+	}
+	stats, err := store.GC(gcDryRun)
+	if err != nil {
+		logger.Error("garbage collection failed", zap.Error(err))
+		return err
+	}
+	logger.Info("garbage collection completed",
+		zap.Bool("dry_run", gcDryRun),
+		zap.Int("forgotten", forgotten),
+		zap.Int("stale_entries", stats.StaleEntries),
+		zap.Int("blobs_removed", stats.Blobs),
+		zap.Int64("bytes_freed", stats.Bytes))
+	return nil
+}
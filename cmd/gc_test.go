@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestRunGC(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// Without a store there is nothing to collect, and nothing should be created.
+	if err := runGC(logger); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat("downloads"); !os.IsNotExist(err) {
+		t.Errorf("expected no download directory, got %v", err)
+	}
+
+	// An orphaned blob, e.g. left by an interrupted run, is removed.
+	orphan := filepath.Join("downloads", "sha256", "ab", "cd", "abcd")
+	if err := os.MkdirAll(filepath.Dir(orphan), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(orphan, []byte("orphan"), 0444); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGC(logger); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Errorf("expected orphaned blob to be removed, got %v", err)
+	}
+}
+
+func TestRunGC_Forget(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	defer func(forget []string) { gcForget = forget }(gcForget)
+
+	// An index entry has no file: its blob is only collected once the entry is forgotten.
+	sum := sha256.Sum256([]byte("payload"))
+	digest := hex.EncodeToString(sum[:])
+	blob := filepath.Join("downloads", "sha256", digest[:2], digest[2:4], digest)
+	if err := os.MkdirAll(filepath.Dir(blob), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(blob, []byte("payload"), 0444); err != nil {
+		t.Fatal(err)
+	}
+	index := `{"entries": {"example.com/app.jar": {"digest": "` + digest + `", "mode": "index"}}, "refs": {"` + digest + `": 1}}`
+	if err := os.WriteFile(filepath.Join("downloads", "cas-index.json"), []byte(index), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gcForget = nil
+	if err := runGC(logger); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(blob); err != nil {
+		t.Fatalf("expected referenced blob to be kept: %v", err)
+	}
+
+	gcForget = []string{"example.com/app.jar", "missing.bin"}
+	if err := runGC(logger); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(blob); !os.IsNotExist(err) {
+		t.Errorf("expected forgotten entry's blob to be removed, got %v", err)
+	}
+}
@@ -2,18 +2,74 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"jfrog-assignment/internal/models"
 	"jfrog-assignment/internal/modules/downloader"
 	"jfrog-assignment/internal/modules/filereader"
+	"jfrog-assignment/internal/modules/normalizer"
 	"jfrog-assignment/internal/modules/persistence"
 	"jfrog-assignment/internal/modules/pipeline"
+	"jfrog-assignment/internal/modules/storage"
+	"jfrog-assignment/internal/modules/webreader"
+	"net/http"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
 	"go.uber.org/zap"
 )
 
-var csvPath string // Path to the CSV file containing URLs, set via command-line flag
+var (
+	inputPaths   []string                           // Input files or glob patterns listing URLs ("-" for stdin), set via command-line flags
+	errorPolicy  string                             // How stage failures are handled ("fail-fast" or "continue"), set via command-line flag
+	retryPolicy  = downloader.DefaultRetryPolicy()  // Download retry settings, set via command-line flags
+	clientConfig = downloader.DefaultClientConfig() // HTTP client timeouts, pool and protocol settings, set via command-line flags
+
+	configPath     string               // Path to an optional JSON configuration file
+	hostLimit      downloader.HostLimit // Default per-host limit, set via command-line flags
+	hostLimitSpecs []string             // Per-host limit overrides, set via command-line flags
+
+	stream   bool   // Whether downloads are spooled to disk instead of memory, set via command-line flag
+	resume   bool   // Whether interrupted downloads are kept and resumed, set via command-line flag
+	spoolDir string // Directory for spooled and partial downloads, set via command-line flag
+
+	incremental bool // Whether unchanged URLs are skipped using ETag/Last-Modified, set via command-line flag
+
+	headerSpecs     []string // Headers sent with every request as "Name: value", set via command-line flags
+	hostHeaderSpecs []string // Per-host headers as "host,Name: value", set via command-line flags
+	useNetrc        bool     // Whether credentials are read from $NETRC or ~/.netrc, set via command-line flag
+	netrcPath       string   // Path to a netrc file with credentials, set via command-line flag
+	cookieJarPath   string   // Path of the JSON file cookies are loaded from and saved to, set via command-line flag
+
+	checksumsPath string // Path to a SHA256SUMS-style file of expected digests, set via command-line flag
+	maxSize       int64  // Maximum body size in bytes (0 = unlimited), set via command-line flag
+
+	sidecars     bool   // Whether a metadata sidecar is saved next to each file, set via command-line flag
+	manifestPath string // Path of the JSON Lines run manifest, set via command-line flag
+
+	output  string // Storage URI files are saved to (a directory, file://, s3:// or http(s)://), set via command-line flag
+	casMode string // How files refer to deduplicated blobs ("hardlink", "symlink" or "index"; empty disables CAS), set via command-line flag
+
+	urlColumn    string // Name or zero-based index of the CSV column holding URLs, set via command-line flag
+	csvDelimiter string // CSV field delimiter, set via command-line flag
+	csvComment   string // CSV comment character, set via command-line flag
+	noHeader     bool   // Whether the CSV file lacks a header row, set via command-line flag
+
+	inputFormat  string // Input format ("auto", "csv", "text", "json", "ndjson" or "yaml"), set via command-line flag
+	urlPointer   string // JSON pointer to the URL within structured input items, set via command-line flag
+	itemsPointer string // JSON pointer to the list of items within JSON and YAML documents, set via command-line flag
+	preferHTTPS  bool   // Whether URLs without a scheme, and http URLs on the default port, use https, set via command-line flag
+
+	sitemapURLs     []string // Sitemaps or sitemap indexes whose URLs are downloaded, set via command-line flags
+	linkPages       []string // HTML pages whose links are downloaded, set via command-line flags
+	includePatterns []string // Regular expressions URLs from sitemaps, pages and crawls must match, set via command-line flags
+	excludePatterns []string // Regular expressions URLs from sitemaps, pages and crawls must not match, set via command-line flags
+
+	naming       string // Output file naming strategy, set via command-line flag
+	nameTemplate string // Output file name template for the template strategy, set via command-line flag
+)
+
+const syncStateFile = ".sync-state.json" // File in the download directory recording validators for --incremental
 
 var rootCmd = &cobra.Command{
 	Use:   "urldownloader",
@@ -26,21 +82,91 @@ var rootCmd = &cobra.Command{
 // Parameters:
 //   - ctx: Context for cancellation and timeouts.
 //   - logger: Logger for application-wide logging.
-func Execute(ctx context.Context, logger *zap.Logger) {
-	rootCmd.Run = func(cmd *cobra.Command, args []string) {
-		run(ctx, logger)
+//
+// Returns:
+//   - The process exit code: 0 on success, 1 if the command or any pipeline stage failed.
+func Execute(ctx context.Context, logger *zap.Logger) int {
+	rootCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		// Flags parsed fine; errors from here on are pipeline failures that are already logged.
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return run(ctx, logger)
+	}
+	gcCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return runGC(logger)
+	}
+	crawlCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return runCrawl(ctx, logger, args)
 	}
 	if err := rootCmd.Execute(); err != nil {
 		logger.Error("execution failed", zap.Error(err))
-		os.Exit(1)
+		return 1
 	}
+	return 0
 }
 
 // init initializes the command-line flags for the root command.
 func init() {
-	rootCmd.Flags().StringVarP(&csvPath, "csv", "c", "", "Path to CSV file containing URLs")
-	pflag.CommandLine.AddFlagSet(rootCmd.Flags())
-	rootCmd.MarkFlagRequired("csv")
+	rootCmd.Flags().StringArrayVarP(&inputPaths, "csv", "c", nil, `Input file or glob pattern listing URLs (CSV, text, JSON, NDJSON or YAML, optionally .gz or .zst); "-" reads stdin (repeatable)`)
+	rootCmd.Flags().StringVar(&inputFormat, "input-format", "auto", "Input format: auto (by file extension), csv, text, json, ndjson or yaml")
+	rootCmd.Flags().StringVar(&urlPointer, "url-pointer", filereader.DefaultURLPointer, "JSON pointer to the URL within JSON, NDJSON and YAML items")
+	rootCmd.Flags().StringArrayVar(&sitemapURLs, "sitemap", nil, "Download the URLs listed in this sitemap or sitemap index, optionally gzipped (repeatable)")
+	rootCmd.Flags().StringArrayVar(&linkPages, "links", nil, "Download the targets of the links on this HTML page (repeatable)")
+	rootCmd.PersistentFlags().StringArrayVar(&includePatterns, "include", nil, "Only download sitemap, page and crawled URLs matching this regular expression (repeatable)")
+	rootCmd.PersistentFlags().StringArrayVar(&excludePatterns, "exclude", nil, "Skip sitemap, page and crawled URLs matching this regular expression (repeatable)")
+	rootCmd.PersistentFlags().BoolVar(&preferHTTPS, "prefer-https", false, "Use https for URLs without a scheme and upgrade http URLs on the default port")
+	rootCmd.Flags().StringVar(&itemsPointer, "items-pointer", "", "JSON pointer to the list of items within JSON and YAML documents (default: the document root)")
+	rootCmd.Flags().StringVar(&urlColumn, "url-column", "", "CSV column holding URLs, by header name or zero-based index (default: first column)")
+	rootCmd.Flags().StringVar(&csvDelimiter, "delimiter", ",", `CSV field delimiter (use "\t" or "tab" for tabs)`)
+	rootCmd.Flags().StringVar(&csvComment, "comment", "", "Ignore CSV lines starting with this character")
+	rootCmd.Flags().BoolVar(&noHeader, "no-header", false, "Treat the first CSV line as data instead of a header")
+	rootCmd.PersistentFlags().StringVar(&naming, "naming", "base64", "Output file naming strategy: base64, sha256, mirror or template")
+	rootCmd.PersistentFlags().StringVar(&nameTemplate, "name-template", "{host}/{basename}{ext}", "Output file name template for --naming template")
+	rootCmd.PersistentFlags().StringVar(&errorPolicy, "on-error", pipeline.FailFast.String(), "Stage failure policy: fail-fast or continue")
+	rootCmd.PersistentFlags().IntVar(&retryPolicy.MaxAttempts, "retries", retryPolicy.MaxAttempts, "Maximum download attempts per URL, including the first one")
+	rootCmd.PersistentFlags().DurationVar(&retryPolicy.BaseDelay, "retry-base-delay", retryPolicy.BaseDelay, "Delay before the first retry, doubled for each further retry")
+	rootCmd.PersistentFlags().DurationVar(&retryPolicy.MaxDelay, "retry-max-delay", retryPolicy.MaxDelay, "Maximum delay between retries (0 = no limit)")
+	rootCmd.PersistentFlags().Float64Var(&retryPolicy.Jitter, "retry-jitter", retryPolicy.Jitter, "Fraction (0-1) of each retry delay that is randomized")
+	rootCmd.PersistentFlags().IntSliceVar(&retryPolicy.RetryableStatus, "retry-status", retryPolicy.RetryableStatus, "HTTP status codes that trigger a retry")
+	rootCmd.PersistentFlags().DurationVar(&clientConfig.ConnectTimeout, "connect-timeout", clientConfig.ConnectTimeout, "Time allowed to establish a connection (0 = no limit)")
+	rootCmd.PersistentFlags().DurationVar(&clientConfig.TLSHandshakeTimeout, "tls-handshake-timeout", clientConfig.TLSHandshakeTimeout, "Time allowed for the TLS handshake (0 = no limit)")
+	rootCmd.PersistentFlags().DurationVar(&clientConfig.ResponseHeaderTimeout, "response-header-timeout", clientConfig.ResponseHeaderTimeout, "Time allowed to receive response headers after sending a request (0 = no limit)")
+	rootCmd.PersistentFlags().DurationVar(&clientConfig.Timeout, "timeout", clientConfig.Timeout, "Time allowed for a whole request attempt, including the body (0 = no limit)")
+	rootCmd.PersistentFlags().IntVar(&clientConfig.MaxIdleConns, "max-idle-conns", clientConfig.MaxIdleConns, "Idle connections kept open across all hosts (0 = no limit)")
+	rootCmd.PersistentFlags().IntVar(&clientConfig.MaxIdleConnsPerHost, "max-idle-conns-per-host", clientConfig.MaxIdleConnsPerHost, "Idle connections kept open to each host")
+	rootCmd.PersistentFlags().IntVar(&clientConfig.MaxConnsPerHost, "max-conns-per-host", clientConfig.MaxConnsPerHost, "Connections to each host, active or idle (0 = no limit)")
+	rootCmd.PersistentFlags().DurationVar(&clientConfig.IdleConnTimeout, "idle-conn-timeout", clientConfig.IdleConnTimeout, "How long idle connections are kept open (0 = no limit)")
+	rootCmd.PersistentFlags().DurationVar(&clientConfig.KeepAlive, "keep-alive", clientConfig.KeepAlive, "Interval of TCP keep-alive probes (negative disables them)")
+	rootCmd.PersistentFlags().BoolVar(&clientConfig.DisableKeepAlives, "disable-keep-alives", false, "Close each connection after a single request")
+	rootCmd.PersistentFlags().BoolVar(&clientConfig.DisableHTTP2, "no-http2", false, "Only use HTTP/1.1")
+	rootCmd.PersistentFlags().IntVar(&clientConfig.MaxRedirects, "max-redirects", clientConfig.MaxRedirects, "Redirects followed per request (0 refuses every redirect)")
+	rootCmd.PersistentFlags().BoolVar(&clientConfig.DenyCrossHostRedirects, "no-cross-host-redirects", false, "Refuse redirects to a host other than the requested one")
+	rootCmd.PersistentFlags().BoolVar(&clientConfig.DenyHTTPSDowngrade, "no-https-downgrade", false, "Refuse redirects from HTTPS to HTTP")
+	rootCmd.PersistentFlags().StringArrayVarP(&headerSpecs, "header", "H", nil, `Header sent with every request as "Name: value"; ${VAR} is expanded from the environment (repeatable)`)
+	rootCmd.PersistentFlags().StringArrayVar(&hostHeaderSpecs, "host-header", nil, `Header sent to one host as "host,Name: value"; ${VAR} is expanded from the environment (repeatable)`)
+	rootCmd.PersistentFlags().BoolVar(&useNetrc, "netrc", false, "Send basic auth credentials from $NETRC or ~/.netrc")
+	rootCmd.PersistentFlags().StringVar(&netrcPath, "netrc-file", "", "Send basic auth credentials from this netrc file")
+	rootCmd.PersistentFlags().StringVar(&cookieJarPath, "cookie-jar", "", "Keep cookies in this JSON file across runs")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to a JSON configuration file")
+	rootCmd.PersistentFlags().Float64Var(&hostLimit.RequestsPerSecond, "host-rps", 0, "Maximum requests per second to each host (0 = unlimited)")
+	rootCmd.PersistentFlags().IntVar(&hostLimit.Burst, "host-burst", 1, "Requests each host may receive back to back before --host-rps applies (at least 1)")
+	rootCmd.PersistentFlags().IntVar(&hostLimit.MaxConcurrency, "host-concurrency", 0, "Maximum concurrent downloads from each host (0 = unlimited)")
+	rootCmd.PersistentFlags().StringArrayVar(&hostLimitSpecs, "host-limit", nil, "Per-host override as host,rps=N,burst=N,concurrency=N (repeatable)")
+	rootCmd.PersistentFlags().BoolVar(&stream, "stream", false, "Spool downloads to disk instead of buffering them in memory")
+	rootCmd.PersistentFlags().BoolVar(&resume, "resume", false, "Keep interrupted downloads as .partial files and resume them with Range requests (implies --stream)")
+	rootCmd.PersistentFlags().BoolVar(&incremental, "incremental", false, "Skip URLs unchanged since the previous run using ETag/Last-Modified")
+	rootCmd.PersistentFlags().Int64Var(&maxSize, "max-size", 0, "Fail downloads whose body is larger than this many bytes (0 = unlimited)")
+	rootCmd.PersistentFlags().StringVar(&checksumsPath, "checksums", "", "Path to a SHA256SUMS-style file of expected digests, matched by URL or file name")
+	rootCmd.PersistentFlags().StringVar(&output, "output", persistence.DefaultDownloadDir, "Where files are saved: a directory, file://path, s3://bucket/prefix or http(s)://repository")
+	rootCmd.PersistentFlags().BoolVar(&sidecars, "sidecars", false, "Save a .meta.json file with the URL, status, headers, size, digest and duration next to each file")
+	rootCmd.PersistentFlags().StringVar(&manifestPath, "manifest", "", "Write a JSON Lines manifest listing every URL with its outcome to this file")
+	rootCmd.PersistentFlags().StringVar(&casMode, "cas", "", "Store payloads once by SHA-256 and make files refer to them: hardlink, symlink or index (default: disabled)")
+	rootCmd.PersistentFlags().StringVar(&spoolDir, "spool-dir", "", "Directory for spooled and partial downloads (default: the system temp directory)")
+	rootCmd.MarkFlagsOneRequired("csv", "sitemap", "links")
 }
 
 // run executes the pipeline to process URLs from the CSV file.
@@ -48,18 +174,333 @@ func init() {
 // Parameters:
 //   - ctx: Context for cancellation and timeouts.
 //   - logger: Logger for logging progress and errors.
-func run(ctx context.Context, logger *zap.Logger) {
-	p := pipeline.New(logger)
-	p.AddStage(filereader.New(csvPath))
-	p.AddStage(downloader.New())
-	p.AddStage(persistence.New())
+//
+// Returns:
+//   - An error if the flags are invalid or a pipeline stage failed, nil otherwise (including on cancellation).
+func run(ctx context.Context, logger *zap.Logger) error {
+	setup, err := setupDownloads(logger)
+	if err != nil {
+		return err
+	}
+	defer setup.close()
+
+	sources, err := urlSources(setup.downloader)
+	if err != nil {
+		return err
+	}
+
+	var normalizerOpts []normalizer.Option
+	if preferHTTPS {
+		normalizerOpts = append(normalizerOpts, normalizer.WithPreferHTTPS())
+	}
+
+	records := pipeline.NewChain(logger, pipeline.Merge(sources...))
+	urls := pipeline.Then(records, normalizer.New(normalizerOpts...))
+	contents := pipeline.Then(urls, setup.downloader)
+	return runPipeline(ctx, logger, pipeline.Then(contents, setup.persister), setup.policy)
+}
+
+// downloadSetup holds what every command downloading URLs builds from the shared command-line flags.
+type downloadSetup struct {
+	policy     pipeline.ErrorPolicy       // How stage failures are handled
+	client     *http.Client               // Client downloads, sitemaps and pages are fetched with
+	downloader *downloader.HTTPDownloader // Stage downloading URL records
+	persister  *persistence.FilePersister // Stage saving downloaded content
+	closers    []func()                   // Functions saving state once the pipeline has finished
+}
+
+// close saves the state kept across runs, such as the cookie jar and the manifest.
+func (s *downloadSetup) close() {
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		s.closers[i]()
+	}
+}
+
+// setupDownloads creates the downloader and persister stages from the download and persistence flags.
+// The flags are read from the root command's persistent flags, which every subcommand inherits.
+//
+// Parameters:
+//   - logger: Logger for reporting state that cannot be saved on close.
+//
+// Returns:
+//   - The setup, whose close method must be called after the pipeline ran, or an error if a flag or the
+//     configuration file is invalid, or a file the flags refer to cannot be loaded.
+func setupDownloads(logger *zap.Logger) (*downloadSetup, error) {
+	policy, err := pipeline.ParseErrorPolicy(errorPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	limits, err := resolveHostLimits(cfg, rootCmd.PersistentFlags())
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := resolveHeaders(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	clientCfg := resolveClientConfig(cfg, rootCmd.PersistentFlags())
+	setup := &downloadSetup{
+		policy: policy,
+		client: downloader.NewClient(clientCfg),
+	}
+	// Release whatever was opened so far if a later flag turns out to be invalid.
+	ok := false
+	defer func() {
+		if !ok {
+			setup.close()
+		}
+	}()
+
+	if cookieJarPath != "" {
+		jar, err := downloader.LoadCookieJar(cookieJarPath)
+		if err != nil {
+			return nil, err
+		}
+		setup.closers = append(setup.closers, func() {
+			if err := jar.Save(); err != nil {
+				logger.Error("saving cookie jar failed", zap.Error(err))
+			}
+		})
+		setup.client.Jar = jar
+	}
+
+	opts := []downloader.Option{
+		downloader.WithClient(setup.client),
+		downloader.WithRetryPolicy(retryPolicy),
+		downloader.WithHostLimits(limits),
+		downloader.WithHeaders(headers),
+		downloader.WithMaxSize(maxSize),
+	}
+	if useNetrc && netrcPath == "" {
+		if netrcPath, err = downloader.DefaultNetrcPath(); err != nil {
+			return nil, err
+		}
+	}
+	if netrcPath != "" {
+		netrc, err := downloader.LoadNetrc(netrcPath)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, downloader.WithNetrc(netrc))
+	}
+	if resume && spoolDir == "" {
+		spoolDir = filepath.Join(os.TempDir(), "urldownloader-partial")
+	}
+	if spoolDir != "" {
+		if err := os.MkdirAll(spoolDir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case resume:
+		opts = append(opts, downloader.WithResume(spoolDir))
+	case stream:
+		opts = append(opts, downloader.WithStreaming(spoolDir))
+	}
 
-	inputChan := make(chan interface{}, 50)
-	close(inputChan) // FileReader generates its own input from CSV
+	if checksumsPath != "" {
+		sums, err := downloader.LoadChecksumFile(checksumsPath)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, downloader.WithChecksums(sums))
+	}
+
+	namer, err := persistence.NewNamer(naming, nameTemplate)
+	if err != nil {
+		return nil, err
+	}
+	store, err := openStorage(clientCfg)
+	if err != nil {
+		return nil, err
+	}
+	// Local bookkeeping lives next to the files, or in the default download directory for remote storage.
+	localDir := persistence.DefaultDownloadDir
+	local, isLocal := store.(*storage.Local)
+	if isLocal {
+		localDir = local.Root()
+	}
+
+	persistOpts := []persistence.Option{persistence.WithStorage(store), persistence.WithNamer(namer)}
+	if incremental {
+		state, err := persistence.LoadSyncState(filepath.Join(localDir, syncStateFile))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, downloader.WithConditionalRequests(state))
+		persistOpts = append(persistOpts, persistence.WithSyncState(state))
+	}
+	if casMode != "" {
+		mode, err := persistence.ParseLinkMode(casMode)
+		if err != nil {
+			return nil, err
+		}
+		if !isLocal {
+			return nil, fmt.Errorf("--cas requires a local --output, got %q", output)
+		}
+		cas, err := persistence.OpenCAS(localDir, mode)
+		if err != nil {
+			return nil, err
+		}
+		persistOpts = append(persistOpts, persistence.WithCAS(cas))
+	}
+
+	if sidecars {
+		persistOpts = append(persistOpts, persistence.WithSidecars())
+	}
+	if manifestPath != "" {
+		manifest, err := persistence.CreateManifest(manifestPath)
+		if err != nil {
+			return nil, err
+		}
+		setup.closers = append(setup.closers, func() {
+			if err := manifest.Close(); err != nil {
+				logger.Error("writing manifest failed", zap.Error(err))
+			}
+		})
+		persistOpts = append(persistOpts, persistence.WithManifest(manifest))
+	}
+
+	setup.downloader = downloader.New(opts...)
+	setup.persister = persistence.New(persistence.DefaultDownloadDir, persistOpts...)
+	ok = true
+	return setup, nil
+}
+
+// openStorage opens the --output storage. Remote storages get a client of their own, without the cookie jar of
+// downloads but with the same timeouts, so that a hung storage endpoint fails instead of blocking the persister.
+//
+// Parameters:
+//   - cfg: The resolved HTTP client settings.
+//
+// Returns:
+//   - The storage, or an error if --output is invalid.
+func openStorage(cfg downloader.ClientConfig) (storage.Storage, error) {
+	return storage.Open(output, downloader.NewClient(cfg))
+}
+
+// runPipeline runs a chain whose first stage generates its own input.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - logger: Logger for logging the outcome.
+//   - p: The chain to run.
+//   - policy: How stage failures are handled.
+//
+// Returns:
+//   - An error if a pipeline stage failed, nil otherwise (including on cancellation).
+func runPipeline(ctx context.Context, logger *zap.Logger, p *pipeline.Chain[struct{}, struct{}], policy pipeline.ErrorPolicy) error {
+	p.SetErrorPolicy(policy)
+
+	inputChan := make(chan struct{})
+	close(inputChan) // The sources generate their own input
 
 	if err := p.Run(ctx, inputChan); err != nil && err != context.Canceled {
 		logger.Error("pipeline execution failed", zap.Error(err))
-		os.Exit(1)
+		return err
 	}
 	logger.Info("application run completed")
+	return nil
+}
+
+// urlSources creates the stages reading URLs from the input files, sitemaps and HTML pages given on the command line.
+//
+// Parameters:
+//   - client: The client sitemaps and pages are fetched with, e.g. the downloader, which sends the configured
+//     headers and netrc credentials along.
+//
+// Returns:
+//   - The source stages, or an error if no source is given or a source flag is invalid.
+func urlSources(client webreader.Doer) ([]pipeline.Stage[struct{}, models.URLRecord], error) {
+	var sources []pipeline.Stage[struct{}, models.URLRecord]
+	if len(inputPaths) > 0 {
+		readerOpts, err := inputOptions()
+		if err != nil {
+			return nil, err
+		}
+		paths, err := filereader.ExpandPaths(inputPaths)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, filereader.NewMulti(paths, readerOpts...))
+	}
+
+	filter, err := webreader.NewFilter(includePatterns, excludePatterns)
+	if err != nil {
+		return nil, err
+	}
+	webOpts := []webreader.Option{webreader.WithClient(client), webreader.WithFilter(filter)}
+	if len(sitemapURLs) > 0 {
+		sources = append(sources, webreader.NewSitemapReader(sitemapURLs, webOpts...))
+	}
+	if len(linkPages) > 0 {
+		sources = append(sources, webreader.NewLinkReader(linkPages, webOpts...))
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no URL source given: use --csv, --sitemap or --links")
+	}
+	return sources, nil
+}
+
+// inputOptions builds the FileReader options from the input command-line flags.
+//
+// Returns:
+//   - The reader options, or an error if the format or a pointer is invalid, or the delimiter or comment
+//     is not a single character.
+func inputOptions() ([]filereader.Option, error) {
+	delimiter, err := parseRune("--delimiter", csvDelimiter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := filereader.ParsePointer(urlPointer); err != nil {
+		return nil, fmt.Errorf("--url-pointer: %w", err)
+	}
+	if _, err := filereader.ParsePointer(itemsPointer); err != nil {
+		return nil, fmt.Errorf("--items-pointer: %w", err)
+	}
+	opts := []filereader.Option{
+		filereader.WithDelimiter(delimiter),
+		filereader.WithHeader(!noHeader),
+		filereader.WithURLPointer(urlPointer),
+		filereader.WithItemsPointer(itemsPointer),
+	}
+	if inputFormat != "" && inputFormat != "auto" {
+		format, err := filereader.ParseFormat(inputFormat)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, filereader.WithFormat(format))
+	}
+	if urlColumn != "" {
+		opts = append(opts, filereader.WithURLColumn(urlColumn))
+	}
+	if csvComment != "" {
+		comment, err := parseRune("--comment", csvComment)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, filereader.WithComment(comment))
+	}
+	return opts, nil
+}
+
+// parseRune converts a single-character flag value into a rune, accepting "\t" and "tab" for a tab.
+func parseRune(flag, value string) (rune, error) {
+	switch value {
+	case `\t`, "tab":
+		return '\t', nil
+	}
+	runes := []rune(value)
+	if len(runes) != 1 {
+		return 0, fmt.Errorf("%s must be a single character, got %q", flag, value)
+	}
+	return runes[0], nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"jfrog-assignment/internal/modules/downloader"
 	"jfrog-assignment/internal/modules/pipeline"
+	"net/http"
 	"os"
 	"testing"
 
@@ -68,7 +69,7 @@ func TestPipeline(t *testing.T) {
 	tmpFile.Close()
 
 	p := pipeline.New(logger)
-	csvPath = tmpFile.Name()
+	inputPaths = []string{tmpFile.Name()}
 	p.AddStage(&mockURLReader{})
 	p.AddStage(&mockURLDownloader{})
 	p.AddStage(&mockContentPersister{})
@@ -89,3 +90,101 @@ func TestPipeline(t *testing.T) {
 		t.Errorf("pipeline execution failed: %v", err)
 	}
 }
+
+func TestRun_MissingCSV(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	// Keep the persister's default download directory out of the source tree.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	inputPaths = []string{"nonexistent.csv"}
+	errorPolicy = "fail-fast"
+
+	if err := run(context.Background(), logger); err == nil {
+		t.Errorf("expected error for missing CSV, got nil")
+	}
+}
+
+func TestParseRune(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  rune
+		expectErr bool
+	}{
+		{value: ",", expected: ','},
+		{value: `\t`, expected: '\t'},
+		{value: "tab", expected: '\t'},
+		{value: "§", expected: '§'},
+		{value: "", expectErr: true},
+		{value: ";;", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRune("--delimiter", tt.value)
+		if tt.expectErr != (err != nil) || got != tt.expected {
+			t.Errorf("parseRune(%q) = %q, %v; expected %q, error %v", tt.value, got, err, tt.expected, tt.expectErr)
+		}
+	}
+}
+
+func TestInputOptions(t *testing.T) {
+	defer func(format, url, items string) { inputFormat, urlPointer, itemsPointer = format, url, items }(inputFormat, urlPointer, itemsPointer)
+
+	tests := []struct {
+		name      string
+		format    string
+		url       string
+		items     string
+		expectErr bool
+	}{
+		{name: "auto", format: "auto", url: "/url"},
+		{name: "explicit format and pointers", format: "jsonl", url: "/link/href", items: "/artifacts"},
+		{name: "unknown format", format: "xml", url: "/url", expectErr: true},
+		{name: "relative URL pointer", format: "auto", url: "url", expectErr: true},
+		{name: "relative items pointer", format: "auto", url: "/url", items: "artifacts", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		inputFormat, urlPointer, itemsPointer = tt.format, tt.url, tt.items
+		if _, err := inputOptions(); tt.expectErr != (err != nil) {
+			t.Errorf("%s: inputOptions() error = %v, expected error %v", tt.name, err, tt.expectErr)
+		}
+	}
+}
+
+func TestURLSources(t *testing.T) {
+	defer func(paths, sitemaps, pages, include []string) {
+		inputPaths, sitemapURLs, linkPages, includePatterns = paths, sitemaps, pages, include
+	}(inputPaths, sitemapURLs, linkPages, includePatterns)
+
+	tests := []struct {
+		name      string
+		paths     []string
+		sitemaps  []string
+		pages     []string
+		include   []string
+		expected  int
+		expectErr bool
+	}{
+		{name: "file", paths: []string{"urls.csv"}, expected: 1},
+		{name: "all sources", paths: []string{"urls.csv"}, sitemaps: []string{"https://example.com/sitemap.xml"}, pages: []string{"https://example.com/"}, expected: 3},
+		{name: "web only", sitemaps: []string{"https://example.com/sitemap.xml"}, include: []string{`\.jar$`}, expected: 1},
+		{name: "none", expectErr: true},
+		{name: "invalid pattern", pages: []string{"https://example.com/"}, include: []string{"("}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		inputPaths, sitemapURLs, linkPages, includePatterns = tt.paths, tt.sitemaps, tt.pages, tt.include
+		sources, err := urlSources(http.DefaultClient)
+		if tt.expectErr != (err != nil) || len(sources) != tt.expected {
+			t.Errorf("%s: got %d sources, error %v; expected %d, error %v", tt.name, len(sources), err, tt.expected, tt.expectErr)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors wrapped into Content.Error, so that consumers can test for a failure cause with errors.Is.
+// The underlying error, if any, stays in the chain as well.
+var (
+	ErrTimeout          = errors.New("timeout")              // A deadline expired while connecting or reading
+	ErrDNS              = errors.New("DNS lookup failed")    // The host name could not be resolved
+	ErrConnRefused      = errors.New("connection refused")   // The server refused the connection
+	ErrTLS              = errors.New("TLS failure")          // The TLS handshake or certificate verification failed
+	ErrSizeLimit        = errors.New("size limit exceeded")  // The body is larger than the configured maximum
+	ErrChecksumMismatch = errors.New("checksum mismatch")    // The payload does not match its expected digest
+	ErrCanceled         = errors.New("canceled")             // The run was canceled before the item finished
+	ErrRedirect         = errors.New("redirect not allowed") // A redirect was refused by the redirect policy
+)
+
+// StatusError reports that the server answered with an unexpected HTTP status. Use errors.As to read the code.
+type StatusError struct {
+	Code int // HTTP status code of the response
+}
+
+// Error returns the message "bad status: <code>".
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("bad status: %d", e.Code)
+}
+
+// ErrorCategory classifies why an item failed, so that stages and reports can react to and group failures
+// without parsing error messages.
+type ErrorCategory string
+
+const (
+	CategoryRequest    ErrorCategory = "request"     // the request could not be built from the input record
+	CategoryDNS        ErrorCategory = "dns"         // the host name could not be resolved
+	CategoryConnection ErrorCategory = "connection"  // the connection was refused, reset or otherwise failed
+	CategoryTimeout    ErrorCategory = "timeout"     // a deadline expired while connecting or reading
+	CategoryTLS        ErrorCategory = "tls"         // the TLS handshake or certificate verification failed
+	CategoryHTTPStatus ErrorCategory = "http_status" // the server answered with an unexpected status
+	CategoryRedirect   ErrorCategory = "redirect"    // a redirect was refused by the redirect policy
+	CategoryBodyRead   ErrorCategory = "body_read"   // the response body could not be read completely
+	CategorySizeLimit  ErrorCategory = "size_limit"  // the body was larger than the configured maximum
+	CategoryChecksum   ErrorCategory = "checksum"    // the payload did not match its expected digest
+	CategoryCanceled   ErrorCategory = "canceled"    // the run was canceled before the item finished
+	CategoryStorage    ErrorCategory = "storage"     // the payload could not be saved
+)
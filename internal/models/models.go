@@ -1,12 +1,92 @@
 package models
 
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
 type URLRecord struct {
-	URL string
+	URL      string
+	Filename string            // target file name, overriding the generated one
+	Checksum string            // expected digest of the payload, e.g. "sha256:<hex>"
+	Headers  map[string]string // extra request headers
+	Priority int               // higher values are downloaded first
+	Subdir   string            // subdirectory of the download directory to save into
+	Source   string            // where the record was found: input file or "stdin", sitemap or page URL, or crawled page linking to URL
+	Line     int               // line of Source the record starts on, 0 if unknown
+	Depth    int               // number of links followed from a crawl seed to reach URL; 0 outside crawls
+}
+
+// Origin returns where the record was found as "source:line", "source", or "" if unknown.
+func (r URLRecord) Origin() string {
+	switch {
+	case r.Source == "":
+		return ""
+	case r.Line <= 0:
+		return r.Source
+	default:
+		return fmt.Sprintf("%s:%d", r.Source, r.Line)
+	}
+}
+
+// OriginField returns a "source" log field holding Origin, or a field that is skipped if the origin is unknown.
+func (r URLRecord) OriginField() zap.Field {
+	if origin := r.Origin(); origin != "" {
+		return zap.String("source", origin)
+	}
+	return zap.Skip()
 }
 
 type Content struct {
-	URL      string
-	Data     []byte
-	Error    error
-	Duration int64 // milliseconds
+	URL         string
+	Record      URLRecord     // the input record the content was downloaded for
+	Data        []byte        // payload, when downloaded in buffered mode
+	Body        io.ReadCloser // payload, when downloaded in streaming mode; the consumer must Close it
+	Size        int64         // payload size in bytes
+	ContentType string        // Content-Type response header
+	Error       error
+	Duration    int64 // milliseconds
+	Attempts    int   // number of HTTP attempts made, including retries
+
+	ErrorCategory ErrorCategory // why Error occurred; empty on success
+
+	StatusCode int         // HTTP status of the last response, 0 if none was received
+	Header     http.Header // headers of the last response
+	FinalURL   string      // URL the last response was served from, after redirects
+	Redirects  []Redirect  // redirects followed to reach FinalURL, in order
+	Proto      string      // protocol of the last response, e.g. "HTTP/1.1" or "HTTP/2.0"
+	TLS        *TLSInfo    // TLS connection details of the last response; nil for plain HTTP
+	BytesRead  int64       // body bytes received with the last response; less than Size when a download was resumed
+
+	ETag         string // ETag response header, used for conditional requests on later runs
+	LastModified string // Last-Modified response header, used for conditional requests on later runs
+	NotModified  bool   // whether the server answered 304 to a conditional request
+}
+
+// ReadCloser pairs a reader with the Close of the stream it reads from, e.g. a decompressor reading a file,
+// or a partly read Body followed by the rest of it.
+type ReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// TLSInfo describes the TLS connection a response was received on.
+type TLSInfo struct {
+	Version      string    `json:"version"`                  // protocol version, e.g. "TLS 1.3"
+	CipherSuite  string    `json:"cipher_suite"`             // negotiated cipher suite
+	ServerName   string    `json:"server_name,omitempty"`    // SNI server name sent by the client
+	PeerSubject  string    `json:"peer_subject,omitempty"`   // subject of the server's leaf certificate
+	PeerIssuer   string    `json:"peer_issuer,omitempty"`    // issuer of the server's leaf certificate
+	PeerNotAfter time.Time `json:"peer_not_after,omitempty"` // expiry of the server's leaf certificate
+}
+
+// Redirect is one hop of a redirect chain.
+type Redirect struct {
+	URL        string `json:"url"`         // URL that answered with the redirect
+	StatusCode int    `json:"status_code"` // redirect status, e.g. 301 or 302
+	Location   string `json:"location"`    // URL the client was redirected to
 }
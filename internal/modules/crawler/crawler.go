@@ -0,0 +1,178 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/normalizer"
+	"jfrog-assignment/internal/modules/pipeline"
+	"jfrog-assignment/internal/modules/webreader"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Scope limits which discovered URLs a crawl follows.
+type Scope string
+
+const (
+	ScopeHost   Scope = "host"   // URLs on the host and port of a seed
+	ScopePrefix Scope = "prefix" // URLs below the directory of a seed, e.g. https://example.com/docs/ for https://example.com/docs/index.html
+)
+
+const (
+	DefaultMaxDepth = 3    // Links followed from a seed unless WithMaxDepth is given
+	DefaultMaxPages = 1000 // URLs downloaded per crawl unless WithMaxPages is given
+)
+
+// ParseScope parses the name of a Scope.
+//
+// Parameters:
+//   - value: "host" or "prefix".
+//
+// Returns:
+//   - The Scope, or an error if value is not a known scope.
+func ParseScope(value string) (Scope, error) {
+	switch scope := Scope(value); scope {
+	case ScopeHost, ScopePrefix:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("unknown crawl scope %q (expected host or prefix)", value)
+	}
+}
+
+// discovery reports that a downloaded URL has been processed, along with the links found in it.
+type discovery struct {
+	parent models.URLRecord // Record of the processed URL
+	links  []webreader.Link // Links found in its content
+}
+
+// Crawler downloads the pages reachable from a set of seed URLs. It consists of two stages sharing the
+// crawl state: the Crawler itself is the frontier, a source stage emitting every URL to download exactly
+// once, and its Extractor sits after the downloader and feeds the links found in downloaded HTML and CSS
+// back to the frontier. The crawl ends once every emitted URL has been processed and no new URL was found.
+// A Crawler runs a single crawl.
+type Crawler struct {
+	seeds       []models.URLRecord // Normalized seed records
+	hosts       map[string]bool    // Hosts of the seeds, for ScopeHost
+	prefixes    []string           // Directories of the seeds, for ScopePrefix
+	scope       Scope              // Which discovered URLs are followed
+	maxDepth    int                // Links followed from a seed
+	maxPages    int                // URLs emitted per crawl; 0 for no limit
+	filter      webreader.Filter   // Filter discovered URLs must pass
+	preferHTTPS bool               // Whether discovered http URLs on the default port are upgraded to https
+	maxDocSize  int64              // Largest document links are extracted from
+	feedback    chan discovery     // Processed URLs and their links, from the Extractor to the frontier
+}
+
+var _ pipeline.Stage[struct{}, models.URLRecord] = (*Crawler)(nil)
+
+// New creates a new Crawler starting from the given seed URLs.
+//
+// Parameters:
+//   - seeds: URLs the crawl starts from; they are downloaded whatever the scope and filter.
+//   - opts: Optional settings; by default DefaultMaxDepth and DefaultMaxPages apply, and the crawl stays
+//     on the hosts of the seeds.
+//
+// Returns:
+//   - A pointer to a new Crawler, or an error if no seed is given or a seed is not a valid URL.
+func New(seeds []string, opts ...Option) (*Crawler, error) {
+	c := &Crawler{
+		hosts:      make(map[string]bool),
+		scope:      ScopeHost,
+		maxDepth:   DefaultMaxDepth,
+		maxPages:   DefaultMaxPages,
+		maxDocSize: webreader.DefaultMaxDocumentSize,
+		feedback:   make(chan discovery, 50),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("no seed URL given")
+	}
+
+	for _, seed := range seeds {
+		normalized, err := normalizer.Normalize(seed, c.preferHTTPS)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seed %q: %w", seed, err)
+		}
+		u, err := url.Parse(normalized)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seed %q: %w", seed, err)
+		}
+		c.hosts[u.Host] = true
+		dir := *u
+		dir.RawQuery, dir.Path, dir.RawPath = "", u.Path[:strings.LastIndex(u.Path, "/")+1], ""
+		if dir.Path == "" {
+			dir.Path = "/"
+		}
+		c.prefixes = append(c.prefixes, dir.String())
+		c.seeds = append(c.seeds, models.URLRecord{URL: normalized, Source: "seed"})
+	}
+	return c, nil
+}
+
+// Execute emits the seeds, then every in-scope URL discovered by the Extractor, shallowest first, until no URL
+// is left to download or the page limit is reached. Downloads finish out of order, so a URL may first be found
+// through a longer path than its shortest one: when a shallower link to it turns up later, it is moved up in the
+// queue, or, if it was already emitted, the links found in it are followed again from the lower depth, so that
+// no link within reach is lost and no URL is downloaded twice.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - input: Input channel (unused, the frontier generates its own data).
+//   - output: Channel to send the URL records to download to.
+//   - logger: Logger for logging progress.
+//
+// Returns:
+//   - ctx.Err() if ctx is canceled, nil otherwise.
+func (c *Crawler) Execute(ctx context.Context, input <-chan struct{}, output chan<- models.URLRecord, logger *zap.Logger) error {
+	f := newFrontier(c)
+	pending := 0 // URLs emitted but not yet processed by the Extractor
+
+	for f.len() > 0 || pending > 0 {
+		var out chan<- models.URLRecord
+		next, ok := f.peek()
+		if ok {
+			out = output
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Warn("crawl interrupted", zap.Error(ctx.Err()))
+			return ctx.Err()
+		case out <- next:
+			f.pop()
+			pending++
+		case d := <-c.feedback:
+			pending--
+			f.process(d)
+		}
+	}
+
+	logger.Info("crawl statistics",
+		zap.Int("pages", f.stats.pages),
+		zap.Int("refollowed", f.stats.refollowed),
+		zap.Int("out_of_scope", f.stats.outOfScope),
+		zap.Int("too_deep", f.stats.tooDeep),
+		zap.Int("filtered", f.stats.filtered),
+		zap.Int("over_limit", f.stats.overLimit),
+		zap.Int("invalid", f.stats.invalid))
+	return nil
+}
+
+// inScope reports whether a normalized URL is within the crawl scope.
+func (c *Crawler) inScope(rawURL string) bool {
+	if c.scope == ScopePrefix {
+		for _, prefix := range c.prefixes {
+			if strings.HasPrefix(rawURL, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	u, err := url.Parse(rawURL)
+	return err == nil && c.hosts[u.Host]
+}
@@ -0,0 +1,291 @@
+package crawler
+
+import (
+	"context"
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/downloader"
+	"jfrog-assignment/internal/modules/pipeline"
+	"jfrog-assignment/internal/modules/webreader"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// site is a small web site whose pages link to each other, to a stylesheet and to another host.
+var site = map[string]string{
+	"/index.html": `<html><head><link rel="stylesheet" href="/style.css"></head>
+<body><a href="/docs/a.html">A</a> <a href="http://elsewhere.invalid/x.html">out</a> <a href="#top">top</a></body></html>`,
+	"/style.css":        `body { background: url("img/bg.png") }`,
+	"/img/bg.png":       "png",
+	"/docs/a.html":      `<a href="/index.html">home</a> <a href="b.html">B</a> <a href="missing.html">gone</a> <img src="/docs/logo.png">`,
+	"/docs/b.html":      `<a href="a.html">A</a> <a href="deep/c.html">C</a>`,
+	"/docs/logo.png":    "png",
+	"/docs/deep/c.html": `<a href="/docs/a.html">A</a> <a href="/docs/deep/d.html">D</a>`,
+}
+
+// serveSite starts a server for site, typing each page by its extension.
+func serveSite(t *testing.T) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := site[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", mime.TypeByExtension(path.Ext(r.URL.Path)))
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// mustFilter creates a webreader.Filter, failing the test if a pattern is invalid.
+func mustFilter(t *testing.T, include, exclude []string) webreader.Filter {
+	t.Helper()
+	f, err := webreader.NewFilter(include, exclude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// recorder is a final stage remembering the records of the contents it receives.
+type recorder struct {
+	mu      sync.Mutex
+	records []models.URLRecord
+}
+
+func (r *recorder) Execute(ctx context.Context, input <-chan models.Content, output chan<- struct{}, logger *zap.Logger) error {
+	for c := range input {
+		if c.Body != nil {
+			c.Body.Close()
+		}
+		r.mu.Lock()
+		r.records = append(r.records, c.Record)
+		r.mu.Unlock()
+	}
+	return nil
+}
+
+// crawl runs a crawl of the test site from the given seed paths and returns the crawled records by path.
+func crawl(t *testing.T, ts *httptest.Server, seeds []string, opts ...Option) map[string]models.URLRecord {
+	t.Helper()
+	for i, seed := range seeds {
+		seeds[i] = ts.URL + seed
+	}
+	c, err := New(seeds, opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger := zaptest.NewLogger(t)
+	rec := &recorder{}
+	urls := pipeline.NewChain(logger, c)
+	contents := pipeline.Then(urls, downloader.New())
+	pages := pipeline.Then(contents, c.Extractor())
+	chain := pipeline.Then(pages, rec)
+
+	input := make(chan struct{})
+	close(input)
+	if err := chain.Run(context.Background(), input); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	crawled := make(map[string]models.URLRecord)
+	for _, r := range rec.records {
+		p := strings.TrimPrefix(r.URL, ts.URL)
+		if _, dup := crawled[p]; dup {
+			t.Errorf("%s crawled twice", p)
+		}
+		crawled[p] = r
+	}
+	return crawled
+}
+
+// paths returns the sorted keys of crawled.
+func paths(crawled map[string]models.URLRecord) []string {
+	var out []string
+	for p := range crawled {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestCrawler(t *testing.T) {
+	tests := []struct {
+		name  string
+		seeds []string
+		opts  []Option
+		want  []string
+	}{
+		{
+			name:  "whole host",
+			seeds: []string{"/index.html"},
+			want: []string{"/docs/a.html", "/docs/b.html", "/docs/deep/c.html", "/docs/logo.png",
+				"/docs/missing.html", "/img/bg.png", "/index.html", "/style.css"},
+		},
+		{
+			name:  "max depth",
+			seeds: []string{"/index.html"},
+			opts:  []Option{WithMaxDepth(1)},
+			want:  []string{"/docs/a.html", "/index.html", "/style.css"},
+		},
+		{
+			name:  "only seeds",
+			seeds: []string{"/index.html", "/docs/b.html"},
+			opts:  []Option{WithMaxDepth(0)},
+			want:  []string{"/docs/b.html", "/index.html"},
+		},
+		{
+			name:  "prefix scope",
+			seeds: []string{"/docs/b.html"},
+			opts:  []Option{WithScope(ScopePrefix)},
+			want:  []string{"/docs/a.html", "/docs/b.html", "/docs/deep/c.html", "/docs/deep/d.html", "/docs/logo.png", "/docs/missing.html"},
+		},
+		{
+			name:  "max pages",
+			seeds: []string{"/index.html"},
+			opts:  []Option{WithMaxPages(2)},
+			want:  []string{"/index.html", "/style.css"},
+		},
+		{
+			name:  "filter",
+			seeds: []string{"/index.html"},
+			opts:  []Option{WithFilter(mustFilter(t, nil, []string{`\.png$`, `/deep/`}))},
+			want:  []string{"/docs/a.html", "/docs/b.html", "/docs/missing.html", "/index.html", "/style.css"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := serveSite(t)
+			got := paths(crawl(t, ts, tt.seeds, tt.opts...))
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("crawled %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrawler_Records(t *testing.T) {
+	ts := serveSite(t)
+	crawled := crawl(t, ts, []string{"/index.html"})
+
+	tests := []struct {
+		path   string
+		depth  int
+		source string
+		line   int
+	}{
+		{path: "/index.html", depth: 0, source: "seed"},
+		{path: "/style.css", depth: 1, source: "/index.html", line: 1},
+		{path: "/docs/a.html", depth: 1, source: "/index.html", line: 2},
+		{path: "/img/bg.png", depth: 2, source: "/style.css", line: 1},
+		{path: "/docs/deep/c.html", depth: 3, source: "/docs/b.html", line: 1},
+	}
+	for _, tt := range tests {
+		rec, ok := crawled[tt.path]
+		if !ok {
+			t.Errorf("%s not crawled", tt.path)
+			continue
+		}
+		source := strings.TrimPrefix(rec.Source, ts.URL)
+		if rec.Depth != tt.depth || source != tt.source || rec.Line != tt.line {
+			t.Errorf("%s: depth %d, source %q, line %d; want %d, %q, %d",
+				tt.path, rec.Depth, source, rec.Line, tt.depth, tt.source, tt.line)
+		}
+	}
+}
+
+func TestCrawler_Canceled(t *testing.T) {
+	ts := serveSite(t)
+	c, err := New([]string{ts.URL + "/index.html"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Nobody reads the output nor reports back, so only the cancellation can end the crawl.
+	if err := c.Execute(ctx, nil, make(chan models.URLRecord), zaptest.NewLogger(t)); err != context.Canceled {
+		t.Errorf("Execute() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name         string
+		seeds        []string
+		wantErr      bool
+		wantPrefixes []string
+	}{
+		{name: "no seeds", wantErr: true},
+		{name: "invalid seed", seeds: []string{"ftp://example.com/"}, wantErr: true},
+		{
+			name:         "prefixes",
+			seeds:        []string{"example.com", "https://example.com/docs/index.html?q=1", "http://example.com:8080/a/b/"},
+			wantPrefixes: []string{"http://example.com/", "https://example.com/docs/", "http://example.com:8080/a/b/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.seeds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if strings.Join(c.prefixes, " ") != strings.Join(tt.wantPrefixes, " ") {
+				t.Errorf("prefixes = %v, want %v", c.prefixes, tt.wantPrefixes)
+			}
+		})
+	}
+}
+
+func TestInScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope Scope
+		url   string
+		want  bool
+	}{
+		{name: "same host", scope: ScopeHost, url: "https://example.com/other/page.html", want: true},
+		{name: "other port", scope: ScopeHost, url: "https://example.com:8443/docs/a.html", want: false},
+		{name: "other host", scope: ScopeHost, url: "https://www.example.com/docs/a.html", want: false},
+		{name: "below prefix", scope: ScopePrefix, url: "https://example.com/docs/guide/a.html", want: true},
+		{name: "outside prefix", scope: ScopePrefix, url: "https://example.com/blog/a.html", want: false},
+		{name: "sibling of prefix", scope: ScopePrefix, url: "https://example.com/docs-old/a.html", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New([]string{"https://example.com/docs/index.html"}, WithScope(tt.scope))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.inScope(tt.url); got != tt.want {
+				t.Errorf("inScope(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	for _, value := range []string{"host", "prefix"} {
+		if scope, err := ParseScope(value); err != nil || string(scope) != value {
+			t.Errorf("ParseScope(%q) = %q, %v", value, scope, err)
+		}
+	}
+	if _, err := ParseScope("domain"); err == nil {
+		t.Error("ParseScope(\"domain\") succeeded, want error")
+	}
+}
@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/pipeline"
+	"jfrog-assignment/internal/modules/webreader"
+	"mime"
+
+	"go.uber.org/zap"
+)
+
+// Extractor is the pipeline.Stage of a Crawler that follows the downloader. It passes every content on
+// unchanged and reports the links found in downloaded HTML pages and stylesheets back to the frontier.
+// Links are reported at every depth, since a page downloaded at the maximum depth may later turn out to be
+// reachable through a shorter path.
+type Extractor struct {
+	crawler *Crawler
+}
+
+var _ pipeline.Stage[models.Content, models.Content] = (*Extractor)(nil)
+
+// Extractor returns the link-extraction stage of the crawl, to be placed after the downloader.
+//
+// Returns:
+//   - A pointer to the Extractor feeding this Crawler.
+func (c *Crawler) Extractor() *Extractor {
+	return &Extractor{crawler: c}
+}
+
+// Execute extracts the links of each content received on the input channel, sends the content to the
+// output channel and reports the links to the frontier. Streamed HTML and CSS bodies are read into memory.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - input: Channel to receive downloaded content from.
+//   - output: Channel to send the content to.
+//   - logger: Logger for logging extraction problems.
+//
+// Returns:
+//   - ctx.Err() if ctx is canceled, nil otherwise.
+func (e *Extractor) Execute(ctx context.Context, input <-chan models.Content, output chan<- models.Content, logger *zap.Logger) error {
+	for content := range input {
+		links, err := e.extract(&content)
+		if err != nil {
+			logger.Warn("link extraction failed", zap.String("url", content.URL), zap.Error(err))
+		}
+		logger.Debug("extracted links", zap.String("url", content.URL), zap.Int("links", len(links)))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case output <- content:
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e.crawler.feedback <- discovery{parent: content.Record, links: links}:
+		}
+	}
+	return nil
+}
+
+// extract returns the links of a successfully downloaded HTML page or stylesheet. A streamed body no larger
+// than the maximum document size is moved to c.Data.
+func (e *Extractor) extract(c *models.Content) ([]webreader.Link, error) {
+	if c.Error != nil || c.NotModified {
+		return nil, nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType)
+	var extract func([]byte, string) ([]webreader.Link, error)
+	switch mediaType {
+	case "text/html", "application/xhtml+xml":
+		extract = webreader.ExtractReferences
+	case "text/css":
+		extract = webreader.ExtractCSSLinks
+	default:
+		return nil, nil
+	}
+
+	if c.Body != nil {
+		data, err := io.ReadAll(io.LimitReader(c.Body, e.crawler.maxDocSize+1))
+		if err != nil || int64(len(data)) > e.crawler.maxDocSize {
+			// Hand the persister what was read followed by the rest of the body.
+			c.Body = models.ReadCloser{Reader: io.MultiReader(bytes.NewReader(data), c.Body), Closer: c.Body}
+			return nil, err
+		}
+		c.Body.Close()
+		c.Body, c.Data = nil, data
+	}
+
+	base := c.FinalURL
+	if base == "" {
+		base = c.URL
+	}
+	return extract(c.Data, base)
+}
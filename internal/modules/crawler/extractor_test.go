@@ -0,0 +1,134 @@
+package crawler
+
+import (
+	"errors"
+	"io"
+	"jfrog-assignment/internal/models"
+	"strings"
+	"testing"
+)
+
+// closeRecorder is a streamed body remembering whether it was closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestExtractor_Extract(t *testing.T) {
+	const page = `<a href="a.html">A</a> <img src="/logo.png">`
+
+	tests := []struct {
+		name    string
+		content models.Content
+		want    []string
+	}{
+		{
+			name:    "html",
+			content: models.Content{URL: "http://example.com/docs/", ContentType: "text/html; charset=utf-8", Data: []byte(page)},
+			want:    []string{"http://example.com/docs/a.html", "http://example.com/logo.png"},
+		},
+		{
+			name: "relative to final URL",
+			content: models.Content{URL: "http://example.com/", FinalURL: "http://example.com/docs/index.html",
+				ContentType: "application/xhtml+xml", Data: []byte(page)},
+			want: []string{"http://example.com/docs/a.html", "http://example.com/logo.png"},
+		},
+		{
+			name:    "css",
+			content: models.Content{URL: "http://example.com/css/site.css", ContentType: "text/css", Data: []byte(`a { background: url(../bg.png) }`)},
+			want:    []string{"http://example.com/bg.png"},
+		},
+		{
+			name:    "other type",
+			content: models.Content{URL: "http://example.com/", ContentType: "text/plain", Data: []byte(page)},
+		},
+		{
+			name:    "failed download",
+			content: models.Content{URL: "http://example.com/", ContentType: "text/html", Data: []byte(page), Error: errors.New("boom")},
+		},
+		{
+			name:    "not modified",
+			content: models.Content{URL: "http://example.com/", ContentType: "text/html", NotModified: true},
+		},
+		{
+			name: "at max depth",
+			content: models.Content{URL: "http://example.com/", ContentType: "text/html", Data: []byte(page),
+				Record: models.URLRecord{Depth: DefaultMaxDepth}},
+			want: []string{"http://example.com/a.html", "http://example.com/logo.png"},
+		},
+	}
+
+	c, err := New([]string{"http://example.com/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, err := c.Extractor().extract(&tt.content)
+			if err != nil {
+				t.Fatalf("extract() error = %v", err)
+			}
+			var got []string
+			for _, link := range links {
+				got = append(got, link.URL)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("extract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractor_Streamed(t *testing.T) {
+	const page = `<a href="a.html">A</a>`
+
+	tests := []struct {
+		name      string
+		maxSize   int64
+		wantLinks int
+		wantData  bool
+	}{
+		{name: "read into memory", maxSize: int64(len(page)), wantLinks: 1, wantData: true},
+		{name: "too large", maxSize: int64(len(page)) - 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New([]string{"http://example.com/"}, WithMaxDocumentSize(tt.maxSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := &closeRecorder{Reader: strings.NewReader(page)}
+			content := models.Content{URL: "http://example.com/", ContentType: "text/html", Body: body}
+
+			links, err := c.Extractor().extract(&content)
+			if err != nil {
+				t.Fatalf("extract() error = %v", err)
+			}
+			if len(links) != tt.wantLinks {
+				t.Errorf("extract() returned %d links, want %d", len(links), tt.wantLinks)
+			}
+
+			if tt.wantData {
+				if content.Body != nil || string(content.Data) != page || !body.closed {
+					t.Errorf("body not moved to Data: Body %v, Data %q, closed %v", content.Body, content.Data, body.closed)
+				}
+				return
+			}
+			// The persister must still receive the whole payload.
+			data, err := io.ReadAll(content.Body)
+			if err != nil || string(data) != page {
+				t.Errorf("reassembled body = %q, %v; want %q", data, err, page)
+			}
+			content.Body.Close()
+			if !body.closed {
+				t.Error("closing the reassembled body did not close the original")
+			}
+		})
+	}
+}
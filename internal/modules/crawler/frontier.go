@@ -0,0 +1,175 @@
+package crawler
+
+import (
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/normalizer"
+	"jfrog-assignment/internal/modules/webreader"
+)
+
+// excluded is the depth recorded for URLs that are out of scope or filtered out, which no link can improve on.
+const excluded = -1
+
+// frontierStats counts what the frontier did with discovered links.
+type frontierStats struct {
+	pages, refollowed, outOfScope, tooDeep, filtered, overLimit, invalid int
+}
+
+// frontier holds the URLs of a crawl waiting to be downloaded, one queue per depth, the shallowest depth
+// each URL was found at and the links found in processed URLs. It is only used by the goroutine running the Crawler.
+type frontier struct {
+	crawler *Crawler
+	levels  [][]models.URLRecord        // Queued records by depth; entries whose URL has since been queued shallower are stale
+	queued  map[string]int              // Depth each queued URL waits at
+	depth   map[string]int              // Shallowest depth each URL was found at, or excluded
+	emitted map[string]bool             // URLs emitted
+	links   map[string][]webreader.Link // Links found in each processed URL below the seeds
+	stats   frontierStats
+}
+
+// newFrontier creates a frontier holding the seeds of c.
+func newFrontier(c *Crawler) *frontier {
+	f := &frontier{
+		crawler: c,
+		queued:  make(map[string]int),
+		depth:   make(map[string]int),
+		emitted: make(map[string]bool),
+		links:   make(map[string][]webreader.Link),
+	}
+	for _, seed := range c.seeds {
+		if _, ok := f.depth[seed.URL]; ok {
+			continue
+		}
+		f.depth[seed.URL] = 0
+		if c.maxPages > 0 && f.stats.pages >= c.maxPages {
+			f.stats.overLimit++
+			continue
+		}
+		f.stats.pages++
+		f.push(seed)
+	}
+	return f
+}
+
+// len returns the number of URLs waiting to be emitted.
+func (f *frontier) len() int {
+	return len(f.queued)
+}
+
+// peek returns the next URL to emit, the first one queued at the lowest depth.
+//
+// Returns:
+//   - The record and true, or false if no URL is waiting.
+func (f *frontier) peek() (models.URLRecord, bool) {
+	for depth, level := range f.levels {
+		for len(level) > 0 && f.queued[level[0].URL] != depth {
+			level = level[1:] // Moved to a lower depth since it was queued here.
+		}
+		f.levels[depth] = level
+		if len(level) > 0 {
+			return level[0], true
+		}
+	}
+	return models.URLRecord{}, false
+}
+
+// pop removes the URL returned by peek.
+func (f *frontier) pop() {
+	rec, ok := f.peek()
+	if !ok {
+		return
+	}
+	f.levels[rec.Depth] = f.levels[rec.Depth][1:]
+	delete(f.queued, rec.URL)
+	f.emitted[rec.URL] = true
+}
+
+// push queues rec at its depth.
+func (f *frontier) push(rec models.URLRecord) {
+	for len(f.levels) <= rec.Depth {
+		f.levels = append(f.levels, nil)
+	}
+	f.levels[rec.Depth] = append(f.levels[rec.Depth], rec)
+	f.queued[rec.URL] = rec.Depth
+}
+
+// process follows the links the Extractor found in the content of d.parent, from the shallowest depth its URL
+// has been found at so far, and keeps them in case a shallower link to it turns up later.
+func (f *frontier) process(d discovery) {
+	parent := d.parent
+	if depth, ok := f.depth[parent.URL]; ok && depth < parent.Depth {
+		parent.Depth = depth
+	}
+	if parent.Depth > 0 {
+		f.links[parent.URL] = d.links
+	}
+	for _, link := range d.links {
+		f.add(link, parent)
+	}
+}
+
+// add decides whether a link found in the content of parent is followed, and queues it if so. A URL already
+// found is only considered again when the link is shallower than every earlier one, and within the maximum depth:
+// it moves up in the queue if it is still waiting, and its links are followed again from the new depth if it
+// was already emitted, so that every URL is emitted at most once.
+func (f *frontier) add(link webreader.Link, parent models.URLRecord) {
+	c := f.crawler
+	normalized, err := normalizer.Normalize(link.URL, c.preferHTTPS)
+	if err != nil {
+		f.stats.invalid++
+		return
+	}
+	depth := parent.Depth + 1
+	best, seen := f.depth[normalized]
+	if seen && best <= depth {
+		return
+	}
+
+	if !seen {
+		switch {
+		case !c.inScope(normalized):
+			f.depth[normalized] = excluded
+			f.stats.outOfScope++
+			return
+		case !c.filter.Match(normalized):
+			f.depth[normalized] = excluded
+			f.stats.filtered++
+			return
+		}
+	}
+	f.depth[normalized] = depth
+	if depth > c.maxDepth {
+		if !seen {
+			f.stats.tooDeep++
+		}
+		return
+	}
+
+	rec := models.URLRecord{URL: normalized, Source: parent.URL, Line: link.Line, Depth: depth}
+	_, waiting := f.queued[normalized]
+	switch {
+	case waiting:
+		// Still queued deeper: move it up without counting another page.
+	case f.emitted[normalized]:
+		f.refollow(rec)
+		return
+	case c.maxPages > 0 && f.stats.pages >= c.maxPages:
+		f.stats.overLimit++
+		return
+	default:
+		f.stats.pages++
+	}
+	f.push(rec)
+}
+
+// refollow follows the links of rec, an emitted URL now found at a lower depth, again from that depth. The links
+// of a URL the Extractor has not reported yet are followed from its new depth by process.
+func (f *frontier) refollow(rec models.URLRecord) {
+	links, ok := f.links[rec.URL]
+	if !ok {
+		return
+	}
+	f.stats.refollowed++
+	for _, link := range links {
+		f.add(link, rec)
+	}
+}
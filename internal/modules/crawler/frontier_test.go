@@ -0,0 +1,126 @@
+package crawler
+
+import (
+	"context"
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/webreader"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// frontierRun drives a Crawler's frontier by hand, standing in for the downloader and the Extractor.
+type frontierRun struct {
+	t      *testing.T
+	c      *Crawler
+	output chan models.URLRecord
+	done   chan error
+}
+
+// startFrontier runs the frontier of a crawl from http://example.com/ with a maximum depth of 3.
+func startFrontier(t *testing.T) *frontierRun {
+	t.Helper()
+	c, err := New([]string{"http://example.com/"}, WithMaxDepth(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unbuffered, so that each report is taken in before the test moves on.
+	c.feedback = make(chan discovery)
+	r := &frontierRun{t: t, c: c, output: make(chan models.URLRecord), done: make(chan error, 1)}
+	go func() {
+		r.done <- c.Execute(context.Background(), nil, r.output, zaptest.NewLogger(t))
+	}()
+	return r
+}
+
+// next receives the next emitted record and checks its path and depth.
+func (r *frontierRun) next(path string, depth int) models.URLRecord {
+	r.t.Helper()
+	select {
+	case rec := <-r.output:
+		if rec.URL != "http://example.com"+path || rec.Depth != depth {
+			r.t.Fatalf("emitted %s at depth %d, want %s at depth %d", rec.URL, rec.Depth, path, depth)
+		}
+		return rec
+	case <-time.After(time.Second):
+		r.t.Fatalf("nothing emitted, want %s", path)
+	}
+	return models.URLRecord{}
+}
+
+// report hands the links found in parent back to the frontier.
+func (r *frontierRun) report(parent models.URLRecord, paths ...string) {
+	var links []webreader.Link
+	for _, path := range paths {
+		links = append(links, webreader.Link{URL: "http://example.com" + path})
+	}
+	r.c.feedback <- discovery{parent: parent, links: links}
+}
+
+// finish checks that the crawl ends without emitting anything else.
+func (r *frontierRun) finish() {
+	r.t.Helper()
+	select {
+	case rec := <-r.output:
+		r.t.Fatalf("unexpected %s at depth %d", rec.URL, rec.Depth)
+	case err := <-r.done:
+		if err != nil {
+			r.t.Fatalf("Execute() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		r.t.Fatal("crawl did not finish")
+	}
+}
+
+// In these tests, / links to /a and /b, /a to /c, and /c and /b both to /x, which links to /y.
+// /c is processed before /b, so /x is first found at depth 3, where its links are not followed,
+// and only later at depth 2.
+
+func TestFrontier_ShallowerLinkWhileQueued(t *testing.T) {
+	r := startFrontier(t)
+	r.report(r.next("/", 0), "/a", "/b")
+	a, b := r.next("/a", 1), r.next("/b", 1)
+	r.report(a, "/c")
+	r.report(r.next("/c", 2), "/x")
+	// /x waits at depth 3 when /b reports it: it moves up instead of being dropped.
+	r.report(b, "/x")
+	x := r.next("/x", 2)
+	if x.Source != "http://example.com/b" {
+		t.Errorf("/x source = %s, want /b", x.Source)
+	}
+	r.report(x, "/y")
+	r.report(r.next("/y", 3))
+	r.finish()
+}
+
+func TestFrontier_ShallowerLinkAfterProcessed(t *testing.T) {
+	r := startFrontier(t)
+	r.report(r.next("/", 0), "/a", "/b")
+	a, b := r.next("/a", 1), r.next("/b", 1)
+	r.report(a, "/c")
+	r.report(r.next("/c", 2), "/x")
+	r.report(r.next("/x", 3), "/y")
+	// /x was already downloaded at depth 3: it is not emitted again, but its links are followed from depth 2.
+	r.report(b, "/x")
+	y := r.next("/y", 3)
+	if y.Source != "http://example.com/x" {
+		t.Errorf("/y source = %s, want /x", y.Source)
+	}
+	r.report(y)
+	r.finish()
+}
+
+func TestFrontier_ShallowerLinkWhileDownloading(t *testing.T) {
+	r := startFrontier(t)
+	r.report(r.next("/", 0), "/a", "/b")
+	a, b := r.next("/a", 1), r.next("/b", 1)
+	r.report(a, "/c")
+	r.report(r.next("/c", 2), "/x")
+	deep := r.next("/x", 3)
+	// /x is still being downloaded at depth 3 when /b reports it: its links are followed from depth 2 once reported.
+	r.report(b, "/x")
+	r.report(deep, "/y")
+	r.report(r.next("/y", 3))
+	r.finish()
+}
@@ -0,0 +1,82 @@
+package crawler
+
+import "jfrog-assignment/internal/modules/webreader"
+
+// Option configures a Crawler.
+type Option func(*Crawler)
+
+// WithMaxDepth sets how many links are followed from a seed. DefaultMaxDepth is used by default.
+//
+// Parameters:
+//   - depth: The maximum depth; 0 only downloads the seeds.
+//
+// Returns:
+//   - An Option setting the maximum depth.
+func WithMaxDepth(depth int) Option {
+	return func(c *Crawler) {
+		c.maxDepth = depth
+	}
+}
+
+// WithMaxPages sets how many URLs a crawl downloads, seeds included. DefaultMaxPages is used by default.
+//
+// Parameters:
+//   - pages: The maximum number of URLs; 0 for no limit.
+//
+// Returns:
+//   - An Option setting the page limit.
+func WithMaxPages(pages int) Option {
+	return func(c *Crawler) {
+		c.maxPages = pages
+	}
+}
+
+// WithScope sets which discovered URLs are followed. ScopeHost is used by default.
+//
+// Parameters:
+//   - scope: The crawl scope.
+//
+// Returns:
+//   - An Option setting the scope.
+func WithScope(scope Scope) Option {
+	return func(c *Crawler) {
+		c.scope = scope
+	}
+}
+
+// WithFilter sets the filter discovered URLs must pass, in addition to the scope. Every URL passes by default.
+//
+// Parameters:
+//   - filter: The filter, e.g. one created with webreader.NewFilter.
+//
+// Returns:
+//   - An Option setting the filter.
+func WithFilter(filter webreader.Filter) Option {
+	return func(c *Crawler) {
+		c.filter = filter
+	}
+}
+
+// WithPreferHTTPS makes seeds without a scheme use https and upgrades http URLs on the default port to https.
+//
+// Returns:
+//   - An Option preferring https.
+func WithPreferHTTPS() Option {
+	return func(c *Crawler) {
+		c.preferHTTPS = true
+	}
+}
+
+// WithMaxDocumentSize sets the largest HTML page or stylesheet links are extracted from. Larger documents
+// are downloaded but not followed. webreader.DefaultMaxDocumentSize is used by default.
+//
+// Parameters:
+//   - size: The limit in bytes.
+//
+// Returns:
+//   - An Option setting the limit.
+func WithMaxDocumentSize(size int64) Option {
+	return func(c *Crawler) {
+		c.maxDocSize = size
+	}
+}
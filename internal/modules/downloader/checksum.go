@@ -0,0 +1,185 @@
+package downloader
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"jfrog-assignment/internal/models"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// Checksum is an expected digest of a payload.
+type Checksum struct {
+	Algorithm string // "sha256", "sha1" or "md5"
+	Digest    string // Lowercase hex-encoded digest
+}
+
+// algorithmsByLength infers the algorithm of a bare hex digest from its length.
+var algorithmsByLength = map[int]string{
+	sha256.Size * 2: "sha256",
+	sha1.Size * 2:   "sha1",
+	md5.Size * 2:    "md5",
+}
+
+// ParseChecksum parses a checksum written as "algorithm:hex" or as a bare hex digest,
+// whose algorithm (SHA-256, SHA-1 or MD5) is inferred from its length.
+//
+// Parameters:
+//   - value: The checksum text.
+//
+// Returns:
+//   - The parsed Checksum, or an error if the algorithm is unsupported or the digest is malformed.
+func ParseChecksum(value string) (Checksum, error) {
+	algorithm, digest, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		digest = algorithm
+		algorithm = algorithmsByLength[len(digest)]
+	}
+	algorithm = strings.ToLower(algorithm)
+	digest = strings.ToLower(digest)
+
+	c := Checksum{Algorithm: algorithm, Digest: digest}
+	h := c.newHash()
+	if h == nil {
+		return c, fmt.Errorf("unsupported checksum %q", value)
+	}
+	if _, err := hex.DecodeString(digest); err != nil || len(digest) != h.Size()*2 {
+		return c, fmt.Errorf("malformed %s digest %q", algorithm, digest)
+	}
+	return c, nil
+}
+
+// String returns the checksum as "algorithm:hex".
+func (c Checksum) String() string {
+	return c.Algorithm + ":" + c.Digest
+}
+
+// newHash returns a hash for the checksum's algorithm, or nil if it is unsupported.
+func (c Checksum) newHash() hash.Hash {
+	switch c.Algorithm {
+	case "sha256":
+		return sha256.New()
+	case "sha1":
+		return sha1.New()
+	case "md5":
+		return md5.New()
+	default:
+		return nil
+	}
+}
+
+// LoadChecksumFile reads a sidecar checksum file in the format written by sha256sum, sha1sum and md5sum:
+// one "<hex digest>  <file name>" entry per line. File names may also be full URLs.
+//
+// Parameters:
+//   - filePath: Path of the checksum file (e.g. SHA256SUMS).
+//
+// Returns:
+//   - The checksums keyed by file name, or an error if the file cannot be read or contains malformed lines.
+func LoadChecksumFile(filePath string) (map[string]Checksum, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sums := make(map[string]Checksum)
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		digest, name, ok := strings.Cut(text, " ")
+		name = strings.TrimPrefix(strings.TrimSpace(name), "*") // "*" marks binary mode
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%s:%d: expected \"<digest>  <file name>\"", filePath, line)
+		}
+		sum, err := ParseChecksum(digest)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filePath, line, err)
+		}
+		sums[name] = sum
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
+
+// expectedChecksum returns the digest a record's payload must match: the record's own checksum,
+// or else a sidecar entry for its full URL, its file name, or the last segment of its URL path.
+//
+// Returns:
+//   - The checksum and true if one applies, or an error if the record's checksum is malformed.
+func (hd *HTTPDownloader) expectedChecksum(rec models.URLRecord) (Checksum, bool, error) {
+	if rec.Checksum != "" {
+		sum, err := ParseChecksum(rec.Checksum)
+		return sum, err == nil, err
+	}
+	if len(hd.checksums) == 0 {
+		return Checksum{}, false, nil
+	}
+
+	keys := []string{rec.URL, rec.Filename}
+	if u, err := url.Parse(rec.URL); err == nil {
+		keys = append(keys, path.Base(u.Path))
+	}
+	for _, key := range keys {
+		if sum, ok := hd.checksums[key]; ok && key != "" {
+			return sum, true, nil
+		}
+	}
+	return Checksum{}, false, nil
+}
+
+// verifyChecksum hashes the payload of c and compares it with expected.
+// A streamed body is read from disk and rewound afterwards, keeping memory use bounded.
+//
+// Returns:
+//   - An error wrapping ErrChecksumMismatch if the digests differ, or an error if the body cannot be read.
+func verifyChecksum(c Content, expected Checksum) error {
+	h := expected.newHash()
+	if c.Body == nil {
+		h.Write(c.Data)
+	} else {
+		seeker, ok := c.Body.(io.ReadSeeker)
+		if !ok {
+			return fmt.Errorf("streamed body cannot be verified")
+		}
+		if _, err := io.Copy(h, seeker); err != nil {
+			return fmt.Errorf("read body for checksum: %w", err)
+		}
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("rewind body after checksum: %w", err)
+		}
+	}
+
+	actual := h.Sum(nil)
+	want, _ := hex.DecodeString(expected.Digest)
+	if !bytes.Equal(actual, want) {
+		return fmt.Errorf("%w: expected %s, got %s:%s", models.ErrChecksumMismatch, expected, expected.Algorithm, hex.EncodeToString(actual))
+	}
+	return nil
+}
+
+// checksumCategory returns the category of an error returned by verifyChecksum: models.CategoryChecksum for a
+// digest mismatch, and models.CategoryBodyRead when the payload could not be read to compute the digest.
+func checksumCategory(err error) models.ErrorCategory {
+	if errors.Is(err, models.ErrChecksumMismatch) {
+		return models.CategoryChecksum
+	}
+	return models.CategoryBodyRead
+}
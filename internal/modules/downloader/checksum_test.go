@@ -0,0 +1,243 @@
+package downloader
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+const checksumPayload = "test content"
+
+func hexDigest(sum []byte) string {
+	return hex.EncodeToString(sum)
+}
+
+func TestParseChecksum(t *testing.T) {
+	sha256Sum := sha256.Sum256([]byte(checksumPayload))
+	sha1Sum := sha1.Sum([]byte(checksumPayload))
+	md5Sum := md5.Sum([]byte(checksumPayload))
+
+	tests := []struct {
+		name      string
+		value     string
+		expected  Checksum
+		expectErr bool
+	}{
+		{
+			name:     "prefixed sha256",
+			value:    "sha256:" + hexDigest(sha256Sum[:]),
+			expected: Checksum{Algorithm: "sha256", Digest: hexDigest(sha256Sum[:])},
+		},
+		{
+			name:     "uppercase prefix and digest",
+			value:    "SHA1:" + strings.ToUpper(hexDigest(sha1Sum[:])),
+			expected: Checksum{Algorithm: "sha1", Digest: hexDigest(sha1Sum[:])},
+		},
+		{
+			name:     "bare sha256",
+			value:    hexDigest(sha256Sum[:]),
+			expected: Checksum{Algorithm: "sha256", Digest: hexDigest(sha256Sum[:])},
+		},
+		{
+			name:     "bare md5",
+			value:    hexDigest(md5Sum[:]),
+			expected: Checksum{Algorithm: "md5", Digest: hexDigest(md5Sum[:])},
+		},
+		{
+			name:      "unsupported algorithm",
+			value:     "sha512:abcd",
+			expectErr: true,
+		},
+		{
+			name:      "wrong length",
+			value:     "sha256:abcd",
+			expectErr: true,
+		},
+		{
+			name:      "not hex",
+			value:     "md5:zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseChecksum(tt.value)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", c)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, c)
+			}
+		})
+	}
+}
+
+func TestLoadChecksumFile(t *testing.T) {
+	sum := sha256.Sum256([]byte(checksumPayload))
+	digest := hexDigest(sum[:])
+
+	path := filepath.Join(t.TempDir(), "SHA256SUMS")
+	content := "# release checksums\n" +
+		digest + "  app.tar.gz\n" +
+		digest + " *app.zip\n" +
+		"\n" +
+		digest + "  http://example.com/other.bin\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sums, err := LoadChecksumFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"app.tar.gz", "app.zip", "http://example.com/other.bin"} {
+		if sums[name].Digest != digest {
+			t.Errorf("expected digest for %s, got %+v", name, sums[name])
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("not-a-digest  app.tar.gz\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadChecksumFile(path); err == nil {
+		t.Error("expected error for malformed line")
+	}
+}
+
+func TestHTTPDownloader_Checksum(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(checksumPayload))
+	}))
+	defer ts.Close()
+
+	good := sha256.Sum256([]byte(checksumPayload))
+	bad := sha256.Sum256([]byte("tampered"))
+	goodMD5 := md5.Sum([]byte(checksumPayload))
+
+	tests := []struct {
+		name           string
+		record         models.URLRecord
+		opts           []Option
+		expectMismatch bool
+		expectErr      bool
+	}{
+		{
+			name:   "record checksum matches",
+			record: models.URLRecord{URL: ts.URL + "/app.bin", Checksum: "sha256:" + hexDigest(good[:])},
+		},
+		{
+			name:   "record md5 matches",
+			record: models.URLRecord{URL: ts.URL + "/app.bin", Checksum: hexDigest(goodMD5[:])},
+		},
+		{
+			name:           "record checksum mismatch",
+			record:         models.URLRecord{URL: ts.URL + "/app.bin", Checksum: "sha256:" + hexDigest(bad[:])},
+			expectMismatch: true,
+		},
+		{
+			name:           "streamed mismatch",
+			record:         models.URLRecord{URL: ts.URL + "/app.bin", Checksum: "sha256:" + hexDigest(bad[:])},
+			opts:           []Option{WithStreaming(t.TempDir())},
+			expectMismatch: true,
+		},
+		{
+			name:   "streamed match",
+			record: models.URLRecord{URL: ts.URL + "/app.bin", Checksum: "sha256:" + hexDigest(good[:])},
+			opts:   []Option{WithStreaming(t.TempDir())},
+		},
+		{
+			name:           "sidecar by basename",
+			record:         models.URLRecord{URL: ts.URL + "/app.bin"},
+			opts:           []Option{WithChecksums(map[string]Checksum{"app.bin": {Algorithm: "sha256", Digest: hexDigest(bad[:])}})},
+			expectMismatch: true,
+		},
+		{
+			name:   "record checksum overrides sidecar",
+			record: models.URLRecord{URL: ts.URL + "/app.bin", Checksum: "sha256:" + hexDigest(good[:])},
+			opts:   []Option{WithChecksums(map[string]Checksum{"app.bin": {Algorithm: "sha256", Digest: hexDigest(bad[:])}})},
+		},
+		{
+			name:      "malformed record checksum",
+			record:    models.URLRecord{URL: ts.URL + "/app.bin", Checksum: "sha256:xyz"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(tt.opts...).fetch(context.Background(), tt.record, logger)
+
+			if mismatch := errors.Is(c.Error, models.ErrChecksumMismatch); mismatch != tt.expectMismatch {
+				t.Fatalf("expected mismatch=%v, got error %v", tt.expectMismatch, c.Error)
+			}
+			if tt.expectMismatch {
+				if c.Data != nil || c.Body != nil {
+					t.Error("expected no payload on checksum mismatch")
+				}
+				if c.ErrorCategory != models.CategoryChecksum {
+					t.Errorf("expected category %q, got %q", models.CategoryChecksum, c.ErrorCategory)
+				}
+				return
+			}
+			if tt.expectErr {
+				if c.Error == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if c.Body != nil {
+				if got := readBody(t, c); got != checksumPayload {
+					t.Errorf("expected body %q after verification, got %q", checksumPayload, got)
+				}
+			} else if string(c.Data) != checksumPayload {
+				t.Errorf("expected data %q, got %q", checksumPayload, c.Data)
+			}
+		})
+	}
+}
+
+func TestChecksumCategory(t *testing.T) {
+	expected := Checksum{Algorithm: "sha256", Digest: strings.Repeat("0", 64)}
+	tests := []struct {
+		name    string
+		content Content
+		want    models.ErrorCategory
+	}{
+		{name: "mismatch", content: Content{Data: []byte(checksumPayload)}, want: models.CategoryChecksum},
+		{name: "unseekable stream", content: Content{Body: io.NopCloser(strings.NewReader(checksumPayload))}, want: models.CategoryBodyRead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyChecksum(tt.content, expected)
+			if err == nil {
+				t.Fatal("expected verification to fail")
+			}
+			if got := checksumCategory(err); got != tt.want {
+				t.Errorf("expected category %q for %v, got %q", tt.want, err, got)
+			}
+		})
+	}
+}
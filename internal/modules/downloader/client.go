@@ -0,0 +1,83 @@
+package downloader
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ClientConfig configures the HTTP client shared by all downloads. Zero durations and sizes mean no limit,
+// except where noted.
+type ClientConfig struct {
+	ConnectTimeout        time.Duration // Time allowed to establish a TCP connection
+	TLSHandshakeTimeout   time.Duration // Time allowed for the TLS handshake
+	ResponseHeaderTimeout time.Duration // Time allowed between sending a request and receiving the response headers
+	Timeout               time.Duration // Time allowed for a whole request, including reading the body
+
+	MaxIdleConns        int           // Idle connections kept across all hosts
+	MaxIdleConnsPerHost int           // Idle connections kept per host; 0 uses http.DefaultMaxIdleConnsPerHost
+	MaxConnsPerHost     int           // Connections per host, whether active or idle
+	IdleConnTimeout     time.Duration // How long an idle connection is kept
+
+	KeepAlive         time.Duration // Interval of TCP keep-alive probes; negative disables them
+	DisableKeepAlives bool          // Whether to close each connection after a single request
+	DisableHTTP2      bool          // Whether to only speak HTTP/1.1
+
+	MaxRedirects           int  // Redirects followed per request; 0 refuses every redirect
+	DenyCrossHostRedirects bool // Whether to refuse redirects to a host other than the requested one
+	DenyHTTPSDowngrade     bool // Whether to refuse redirects from HTTPS to HTTP
+}
+
+// DefaultClientConfig returns the client settings used when none are configured: bounded connect, handshake and
+// response header timeouts, no total timeout so large bodies can take as long as they need, an idle pool sized
+// for the download worker pool, and up to 10 redirects to any host.
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		ConnectTimeout:        30 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		MaxIdleConns:          maxWorkers * 2,
+		MaxIdleConnsPerHost:   maxWorkers,
+		IdleConnTimeout:       90 * time.Second,
+		KeepAlive:             30 * time.Second,
+		MaxRedirects:          10,
+	}
+}
+
+// NewClient creates an HTTP client with its own connection pool, configured by cfg.
+//
+// Parameters:
+//   - cfg: Timeouts, pool sizes and protocol settings.
+//
+// Returns:
+//   - A client that is safe to share between goroutines.
+func NewClient(cfg ClientConfig) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   cfg.ConnectTimeout,
+		KeepAlive: cfg.KeepAlive,
+	}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		DisableKeepAlives:     cfg.DisableKeepAlives,
+		// A custom dialer disables HTTP/2 unless it is requested explicitly.
+		ForceAttemptHTTP2: !cfg.DisableHTTP2,
+	}
+	if cfg.DisableHTTP2 {
+		// A non-nil, empty map stops the transport from negotiating HTTP/2 over TLS.
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: checkRedirect(cfg),
+		Timeout:       cfg.Timeout,
+	}
+}
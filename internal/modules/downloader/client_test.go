@@ -0,0 +1,129 @@
+package downloader
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"jfrog-assignment/internal/models"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// trustServer makes client trust the certificate of the TLS test server ts.
+func trustServer(client *http.Client, ts *httptest.Server) {
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	client.Transport.(*http.Transport).TLSClientConfig = &tls.Config{RootCAs: pool}
+}
+
+func TestNewClient_Protocols(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("test content"))
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	tests := []struct {
+		name         string
+		disableHTTP2 bool
+		proto        string
+	}{
+		{"HTTP/2", false, "HTTP/2.0"},
+		{"HTTP/2 disabled", true, "HTTP/1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultClientConfig()
+			cfg.DisableHTTP2 = tt.disableHTTP2
+			client := NewClient(cfg)
+			trustServer(client, ts)
+
+			c := New(WithClient(client)).fetch(context.Background(), models.URLRecord{URL: ts.URL}, logger)
+			if c.Error != nil {
+				t.Fatalf("unexpected error: %v", c.Error)
+			}
+			if c.Proto != tt.proto {
+				t.Errorf("expected %s, got %s", tt.proto, c.Proto)
+			}
+			if c.TLS == nil || c.TLS.Version == "" || c.TLS.CipherSuite == "" || c.TLS.PeerNotAfter.IsZero() {
+				t.Errorf("expected TLS details, got %+v", c.TLS)
+			}
+		})
+	}
+}
+
+func TestNewClient_Timeouts(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-body" {
+			w.(http.Flusher).Flush()
+		}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	tests := []struct {
+		name   string
+		path   string
+		config func(*ClientConfig)
+	}{
+		{"response header timeout", "/slow-headers", func(cfg *ClientConfig) { cfg.ResponseHeaderTimeout = 50 * time.Millisecond }},
+		{"total timeout", "/slow-body", func(cfg *ClientConfig) { cfg.Timeout = 50 * time.Millisecond }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultClientConfig()
+			tt.config(&cfg)
+			hd := New(WithClient(NewClient(cfg)), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+			c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL + tt.path}, logger)
+			if !errors.Is(c.Error, models.ErrTimeout) || c.ErrorCategory != models.CategoryTimeout {
+				t.Errorf("expected timeout, got %q: %v", c.ErrorCategory, c.Error)
+			}
+		})
+	}
+}
+
+func TestHTTPDownloader_SharesConnections(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	var conns atomic.Int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("test content"))
+	}))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	hd := New()
+	for i := 0; i < 3; i++ {
+		if c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL}, logger); c.Error != nil {
+			t.Fatalf("unexpected error: %v", c.Error)
+		}
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("expected sequential downloads to reuse one connection, got %d", n)
+	}
+}
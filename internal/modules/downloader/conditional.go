@@ -0,0 +1,36 @@
+package downloader
+
+import "net/http"
+
+// ValidatorSource provides the cache validators recorded for a URL by a previous run.
+type ValidatorSource interface {
+	// Validators returns the ETag and Last-Modified values stored for url, and false if the URL
+	// has no usable record (for example, because its file no longer exists).
+	Validators(url string) (etag, lastModified string, ok bool)
+}
+
+// applyConditional adds If-None-Match and If-Modified-Since headers to req for a URL with stored validators.
+//
+// Parameters:
+//   - req: The request to modify.
+//   - src: The validator source; may be nil.
+//   - url: The URL being downloaded.
+//
+// Returns:
+//   - true if a conditional header was added, so a 304 response means the content is unchanged.
+func applyConditional(req *http.Request, src ValidatorSource, url string) bool {
+	if src == nil {
+		return false
+	}
+	etag, lastModified, ok := src.Validators(url)
+	if !ok || (etag == "" && lastModified == "") {
+		return false
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+	return true
+}
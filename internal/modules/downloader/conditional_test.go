@@ -0,0 +1,89 @@
+package downloader
+
+import (
+	"context"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+type staticValidators map[string][2]string
+
+func (s staticValidators) Validators(url string) (string, string, bool) {
+	v, ok := s[url]
+	return v[0], v[1], ok
+}
+
+func TestHTTPDownloader_Conditional(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	const etag = `"v2"`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte("test content"))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name              string
+		validators        staticValidators
+		expectNotModified bool
+	}{
+		{
+			name:              "unchanged",
+			validators:        staticValidators{ts.URL: {etag, ""}},
+			expectNotModified: true,
+		},
+		{
+			name:              "changed",
+			validators:        staticValidators{ts.URL: {`"v1"`, ""}},
+			expectNotModified: false,
+		},
+		{
+			name:              "unknown URL",
+			validators:        staticValidators{},
+			expectNotModified: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hd := New(WithConditionalRequests(tt.validators))
+			c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL}, logger)
+			if c.Error != nil {
+				t.Fatalf("unexpected error: %v", c.Error)
+			}
+			if c.NotModified != tt.expectNotModified {
+				t.Errorf("expected NotModified=%v, got %v", tt.expectNotModified, c.NotModified)
+			}
+			if c.ETag != etag {
+				t.Errorf("expected ETag %s, got %s", etag, c.ETag)
+			}
+			if !tt.expectNotModified && string(c.Data) != "test content" {
+				t.Errorf("expected content, got %q", c.Data)
+			}
+		})
+	}
+}
+
+func TestHTTPDownloader_NotModifiedWithoutConditional(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	// A 304 to an unconditional request is a server error, not an unchanged resource.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer ts.Close()
+
+	c := New().fetch(context.Background(), models.URLRecord{URL: ts.URL}, logger)
+	if c.Error == nil || c.NotModified {
+		t.Errorf("expected error without NotModified, got %+v", c)
+	}
+}
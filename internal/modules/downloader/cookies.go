@@ -0,0 +1,154 @@
+package downloader
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jfrog-assignment/internal/modules/storage"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// savedCookie is the on-disk form of a cookie in a CookieJar file.
+type savedCookie struct {
+	URL      string    `json:"url"` // URL of the response that set the cookie
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	Expires  time.Time `json:"expires,omitempty"` // Zero for session cookies
+	Secure   bool      `json:"secure,omitempty"`
+	HttpOnly bool      `json:"http_only,omitempty"`
+}
+
+// key identifies the cookie within the jar, so a later cookie with the same name, domain and path replaces it.
+func (c savedCookie) key(host string) string {
+	return host + ";" + c.Domain + ";" + c.Path + ";" + c.Name
+}
+
+// CookieJar is an http.CookieJar whose cookies can be saved to a JSON file and loaded on the next run,
+// so that sessions survive between runs. Session cookies are kept as well. It is safe for concurrent use.
+type CookieJar struct {
+	mu      sync.Mutex
+	jar     *cookiejar.Jar         // Jar deciding which cookies are sent with a request
+	path    string                 // File the cookies are loaded from and saved to
+	cookies map[string]savedCookie // Cookies to save, keyed by savedCookie.key
+	now     func() time.Time       // Clock used for expiry; time.Now outside of tests
+}
+
+var _ http.CookieJar = (*CookieJar)(nil)
+
+// LoadCookieJar creates a cookie jar backed by the file at path, loading the cookies it holds, if it exists.
+// Expired cookies are dropped.
+//
+// Parameters:
+//   - path: Path of the JSON file.
+//
+// Returns:
+//   - The cookie jar, or an error if the file exists but cannot be read or parsed.
+func LoadCookieJar(path string) (*CookieJar, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return nil, err
+	}
+	j := &CookieJar{jar: jar, path: path, cookies: make(map[string]savedCookie), now: time.Now}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cookie jar: %w", err)
+	}
+	var saved []savedCookie
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("parse cookie jar %s: %w", path, err)
+	}
+	for _, c := range saved {
+		u, err := url.Parse(c.URL)
+		if err != nil {
+			return nil, fmt.Errorf("parse cookie jar %s: %w", path, err)
+		}
+		j.SetCookies(u, []*http.Cookie{{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  c.Expires,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}})
+	}
+	return j, nil
+}
+
+// SetCookies implements http.CookieJar, recording the cookies so they can be saved.
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := j.now()
+	origin := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String()
+	for _, c := range cookies {
+		saved := savedCookie{
+			URL:      origin,
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  c.Expires,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}
+		if c.MaxAge > 0 {
+			saved.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		}
+		key := saved.key(u.Host)
+		if c.MaxAge < 0 || (!saved.Expires.IsZero() && !saved.Expires.After(now)) {
+			delete(j.cookies, key)
+			continue
+		}
+		j.cookies[key] = saved
+	}
+	j.jar.SetCookies(u, cookies)
+}
+
+// Cookies implements http.CookieJar.
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+// Save writes the cookies that have not expired to the jar's file, readable only by the current user.
+//
+// Returns:
+//   - An error if the file cannot be written.
+func (j *CookieJar) Save() error {
+	j.mu.Lock()
+	now := j.now()
+	saved := make([]savedCookie, 0, len(j.cookies))
+	for _, c := range j.cookies {
+		if c.Expires.IsZero() || c.Expires.After(now) {
+			saved = append(saved, c)
+		}
+	}
+	j.mu.Unlock()
+
+	slices.SortFunc(saved, func(a, b savedCookie) int {
+		return cmp.Or(strings.Compare(a.URL, b.URL), strings.Compare(a.Name, b.Name))
+	})
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return storage.WriteFileAtomic(j.path, bytes.NewReader(data), 0600)
+}
@@ -0,0 +1,86 @@
+package downloader
+
+import (
+	"context"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestCookieJar_PersistsAcrossRuns(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", MaxAge: 3600})
+			http.SetCookie(w, &http.Cookie{Name: "stale", Value: "x", Path: "/", MaxAge: 3600})
+		case "/logout":
+			http.SetCookie(w, &http.Cookie{Name: "stale", Path: "/", MaxAge: -1})
+		default:
+			if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if _, err := r.Cookie("stale"); err == nil {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			w.Write([]byte("secret"))
+		}
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	download := func(jar *CookieJar, path string) Content {
+		client := NewClient(DefaultClientConfig())
+		client.Jar = jar
+		hd := New(WithClient(client), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+		return hd.fetch(context.Background(), models.URLRecord{URL: ts.URL + path}, logger)
+	}
+
+	// First run: log in, then drop one of the cookies.
+	jar, err := LoadCookieJar(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"/login", "/logout"} {
+		if c := download(jar, p); c.Error != nil {
+			t.Fatalf("%s: unexpected error: %v", p, c.Error)
+		}
+	}
+	if err := jar.Save(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected cookie jar readable by the owner only, got %v", info.Mode().Perm())
+	}
+
+	// Second run: the session cookie is loaded from the file.
+	jar, err = LoadCookieJar(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := download(jar, "/data"); c.Error != nil || string(c.Data) != "secret" {
+		t.Errorf("expected the saved session to be sent, got %q, %v", c.Data, c.Error)
+	}
+}
+
+func TestLoadCookieJar_Malformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCookieJar(path); err == nil {
+		t.Errorf("expected error for malformed jar, got nil")
+	}
+}
@@ -2,11 +2,12 @@ package downloader
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/pipeline"
 	"net/http"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,72 +19,176 @@ import (
 type Content = models.Content
 
 // HTTPDownloader implements both URLDownloader and pipeline.Stage for downloading content from URLs.
-type HTTPDownloader struct{}
+type HTTPDownloader struct {
+	client   *http.Client // Client shared by all downloads
+	retry    RetryPolicy  // Retry policy applied to every URL
+	limiter  *hostLimiter // Per-host rate limits and concurrency caps
+	stream   bool         // Whether bodies are spooled to disk instead of buffered in memory
+	resume   bool         // Whether interrupted downloads are kept as .partial files and resumed
+	spoolDir string       // Directory for spooled bodies in streaming mode
+	maxSize  int64        // Maximum body size in bytes; 0 for no limit
+
+	headers RequestHeaders // Global and per-host headers added to every request
+	netrc   *Netrc         // Basic auth credentials by host, if enabled
+
+	validators ValidatorSource     // Validators from previous runs for conditional requests, if enabled
+	checksums  map[string]Checksum // Expected digests from a sidecar checksum file, keyed by URL or file name
+}
 
 const maxWorkers = 50 // Maximum number of concurrent download workers
 
+var _ pipeline.Stage[models.URLRecord, Content] = (*HTTPDownloader)(nil)
+
 // New creates a new HTTPDownloader instance.
 //
+// Parameters:
+//   - opts: Optional settings; by default DefaultRetryPolicy and a client configured with DefaultClientConfig
+//     are used, and hosts are not limited.
+//
 // Returns:
 //   - A pointer to a new HTTPDownloader instance.
-func New() *HTTPDownloader {
-	return &HTTPDownloader{}
+func New(opts ...Option) *HTTPDownloader {
+	hd := &HTTPDownloader{
+		client:  NewClient(DefaultClientConfig()),
+		retry:   DefaultRetryPolicy(),
+		limiter: newHostLimiter(HostLimits{}),
+	}
+	for _, opt := range opts {
+		opt(hd)
+	}
+	return hd
 }
 
-// Execute downloads content from URLs received on the input channel and sends results to the output channel.
+// Execute downloads content for URL records received on the input channel and sends results to the output channel.
+// While all workers are busy, incoming records are queued and dispatched highest priority first. A record only
+// takes a worker once its host has a free concurrency slot, so a saturated host never holds up the others.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeouts.
-//   - input: Channel to receive URLs from as interface{}.
-//   - output: Channel to send downloaded content to as interface{}.
+//   - input: Channel to receive URL records from.
+//   - output: Channel to send downloaded content to.
 //   - logger: Logger for logging progress and errors.
 //
 // Returns:
 //   - An error if execution fails (currently always nil unless context is canceled).
-func (hd *HTTPDownloader) Execute(ctx context.Context, input <-chan interface{}, output chan<- interface{}, logger *zap.Logger) error {
+func (hd *HTTPDownloader) Execute(ctx context.Context, input <-chan models.URLRecord, output chan<- Content, logger *zap.Logger) error {
 	var (
-		wg           sync.WaitGroup
-		semaphore    = make(chan struct{}, maxWorkers)
-		successCount int32
-		failCount    int32
-		totalDur     int64
+		wg             sync.WaitGroup
+		semaphore      = make(chan struct{}, maxWorkers)
+		pending        recordQueue
+		failures       = newFailureSummary()
+		successCount   int32
+		unchangedCount int32
+		checksumCount  int32
+		failCount      int32
+		totalDur       int64
+
+		next     *queuedRecord                     // Record holding a host slot, waiting for a worker
+		release  func()                            // Releases the host slot of next
+		parked   = make(map[string][]queuedRecord) // Records set aside until their host frees a slot, by host
+		nParked  int                               // Number of parked records
+		freed    = make(chan string)               // Hosts whose workers released their slot
+		finished = make(chan struct{})             // Closed once no more records are dispatched
 	)
+	// Workers stop reporting freed hosts once dispatching is over.
+	stopDispatch := func() {
+		close(finished)
+		if next != nil {
+			release()
+		}
+	}
 
-	for url := range input {
-		urlStr, ok := url.(string)
-		if !ok {
-			logger.Warn("invalid input type, expected string", zap.Any("type", url))
-			continue
+	in := input
+	for in != nil || pending.Len() > 0 || next != nil || nParked > 0 {
+		if ctx.Err() != nil {
+			logger.Warn("download interrupted", zap.Error(ctx.Err()))
+			stopDispatch()
+			wg.Wait()
+			return ctx.Err()
+		}
+
+		// Reserve the host slot of the highest-priority record that can start, parking those of saturated hosts.
+		for next == nil && pending.Len() > 0 {
+			item := pending.popQueued()
+			if done, ok := hd.limiter.tryAcquire(item.record.URL); ok {
+				next, release = &item, done
+				break
+			}
+			host := hostOf(item.record.URL)
+			parked[host] = append(parked[host], item)
+			nParked++
+		}
+
+		// Only offer a worker slot when a record is ready, and stop reading while the queue is full.
+		var slot chan struct{}
+		if next != nil {
+			slot = semaphore
 		}
+		recv := in
+		if pending.Len()+nParked >= maxPending {
+			recv = nil
+		}
+
 		select {
 		case <-ctx.Done():
-			logger.Warn("download interrupted", zap.Error(ctx.Err()))
-			return ctx.Err()
-		default:
+		case host := <-freed:
+			for _, item := range parked[host] {
+				pending.requeue(item)
+			}
+			nParked -= len(parked[host])
+			delete(parked, host)
+		case rec, ok := <-recv:
+			if !ok {
+				in = nil
+				continue
+			}
+			pending.push(rec)
+		case slot <- struct{}{}:
+			rec, done := next.record, release
+			next, release = nil, nil
 			wg.Add(1)
-			semaphore <- struct{}{}
-
-			go func(url string) {
+			go func(rec models.URLRecord) {
 				defer wg.Done()
 				defer func() { <-semaphore }()
 
+				url := rec.URL
 				logger.Debug("downloading URL", zap.String("url", url))
-				content := downloadURL(ctx, url)
+				content := hd.fetch(ctx, rec, logger)
+				done()
+				select {
+				case freed <- hostOf(url):
+				case <-finished:
+				}
 				output <- content
 
 				if content.Error != nil {
+					failures.add(content)
+				}
+				if errors.Is(content.Error, models.ErrChecksumMismatch) {
+					logger.Warn("checksum verification failed",
+						zap.String("url", url),
+						rec.OriginField(),
+						zap.Error(content.Error))
+					atomic.AddInt32(&checksumCount, 1)
+				} else if content.Error != nil {
 					logger.Warn("download failed",
 						zap.String("url", url),
+						rec.OriginField(),
+						zap.Int("attempts", content.Attempts),
 						zap.Error(content.Error))
 					atomic.AddInt32(&failCount, 1)
+				} else if content.NotModified {
+					logger.Debug("content unchanged", zap.String("url", url))
+					atomic.AddInt32(&unchangedCount, 1)
 				} else {
 					logger.Debug("download successful", zap.String("url", url))
 					atomic.AddInt32(&successCount, 1)
 					atomic.AddInt64(&totalDur, content.Duration)
 				}
-			}(urlStr)
+			}(rec)
 		}
 	}
+	stopDispatch()
 
 	wg.Wait()
 
@@ -94,71 +199,290 @@ func (hd *HTTPDownloader) Execute(ctx context.Context, input <-chan interface{},
 
 	logger.Info("download statistics",
 		zap.Int32("successful", successCount),
+		zap.Int32("unchanged", unchangedCount),
+		zap.Int32("checksum_failed", checksumCount),
 		zap.Int32("failed", failCount),
 		zap.Float64("avg_duration_ms", avgDur))
+	failures.log(logger)
 	return nil
 }
 
-// downloadURL performs the HTTP request to download content from a single URL.
+// retryHint describes whether a failed attempt may be retried and how long the server asked to wait.
+type retryHint struct {
+	retryable     bool          // Whether the failure is transient
+	retryAfter    time.Duration // Delay requested by a Retry-After header
+	hasRetryAfter bool          // Whether the response carried a valid Retry-After header
+}
+
+// fetch downloads a single URL whose host concurrency slot is already held, retrying transient failures
+// according to the retry policy.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeouts.
-//   - url: The URL to download.
+//   - rec: The URL record to download.
+//   - logger: Logger for logging retries.
 //
 // Returns:
-//   - A Content struct with the result (data or error) and duration.
-func downloadURL(ctx context.Context, url string) Content {
+//   - A Content struct with the result of the last attempt, the record, the number of attempts, and the total duration.
+func (hd *HTTPDownloader) fetch(ctx context.Context, rec models.URLRecord, logger *zap.Logger) Content {
 	start := time.Now()
+	url := rec.URL
+	maxAttempts := hd.retry.attempts()
 
-	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		url = "http://" + url
+	expected, verify, err := hd.expectedChecksum(rec)
+	if err != nil {
+		content := failure(url, "invalid checksum", err, models.CategoryRequest)
+		content.Record = rec
+		content.Duration = 1
+		return content
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	var content Content
+	for attempt := 1; err == nil; attempt++ {
+		if err = hd.limiter.wait(ctx, url); err != nil {
+			break
+		}
+
+		var hint retryHint
+		content, hint = hd.downloadURL(ctx, rec)
+		content.Attempts = attempt
+		if content.Error == nil || !hint.retryable || attempt >= maxAttempts {
+			break
+		}
+
+		delay := hd.retry.backoff(attempt)
+		if hint.hasRetryAfter {
+			if hd.retry.MaxDelay > 0 && hint.retryAfter > hd.retry.MaxDelay {
+				logger.Debug("server asked to wait longer than the maximum retry delay",
+					zap.String("url", url),
+					zap.Duration("retry_after", hint.retryAfter))
+				break
+			}
+			if hint.retryAfter > delay {
+				delay = hint.retryAfter
+			}
+		}
+
+		logger.Debug("retrying download",
+			zap.String("url", url),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(content.Error))
+
+		if !sleep(ctx, delay) {
+			break
+		}
+	}
 	if err != nil {
-		return Content{
-			URL:      url,
-			Error:    fmt.Errorf("request creation failed: %v", err),
-			Duration: time.Since(start).Milliseconds(),
+		attempts := content.Attempts
+		content = failure(url, "waiting for host limit failed", err, models.CategoryCanceled)
+		content.Attempts = attempts
+	}
+
+	if verify && content.Error == nil && !content.NotModified {
+		if err := verifyChecksum(content, expected); err != nil {
+			if content.Body != nil {
+				content.Body.Close()
+			}
+			content.Data, content.Body = nil, nil
+			content.Error = err
+			content.ErrorCategory = checksumCategory(err)
 		}
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	content.Record = rec
+	content.Duration = time.Since(start).Milliseconds()
+	if content.Duration == 0 {
+		content.Duration = 1
+	}
+	return content
+}
+
+// sleep waits for the given delay.
+//
+// Returns:
+//   - false if ctx was canceled before the delay elapsed, true otherwise.
+func sleep(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// downloadURL performs a single HTTP request to download content from a URL.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - rec: The URL record; its URL must be absolute (see the normalizer stage for completing URLs without a
+//     scheme), and its headers override configured headers.
+//
+// Returns:
+//   - A Content struct with the result (data or error).
+//   - A retryHint describing whether a failure may be retried.
+func (hd *HTTPDownloader) downloadURL(ctx context.Context, rec models.URLRecord) (Content, retryHint) {
+	url, headers := rec.URL, rec.Headers
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return failure(url, "request creation failed", err, models.CategoryRequest), retryHint{}
+	}
+
+	hd.authorize(req, headers)
+	conditional := applyConditional(req, hd.validators, url)
+
+	var partial *partialFile
+	if hd.resume {
+		partial = openPartial(hd.spoolDir, rec)
+		partial.prepare(req)
+	}
+
+	resp, err := hd.clientFor(headers).Do(req)
 	if err != nil {
-		return Content{
-			URL:      url,
-			Error:    fmt.Errorf("download failed: %v", err),
-			Duration: time.Since(start).Milliseconds(),
+		content := failure(url, "download failed", err, models.CategoryConnection)
+		if resp != nil {
+			// A refused redirect still returns the response that asked for it, with its body closed.
+			content.StatusCode = resp.StatusCode
+			content.Header = resp.Header
+			content.FinalURL = resp.Request.URL.String()
+			location := resp.Header.Get("Location")
+			if next, err := resp.Location(); err == nil {
+				location = next.String()
+			}
+			content.Redirects = append(redirectChain(resp), redirectOf(resp, location))
 		}
+		return content, retryHint{retryable: isRetryableError(ctx, err)}
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return Content{
-			URL:      url,
-			Error:    fmt.Errorf("bad status: %d", resp.StatusCode),
-			Duration: time.Since(start).Milliseconds(),
+	if conditional && resp.StatusCode == http.StatusNotModified {
+		etag, lastModified, _ := hd.validators.Validators(url)
+		content := responseContent(url, resp)
+		content.ETag = etag
+		content.LastModified = lastModified
+		content.NotModified = true
+		return content, retryHint{}
+	}
+
+	content := responseContent(url, resp)
+	if partial != nil && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		// The partial file no longer matches the resource; start over on the next attempt.
+		partial.discard()
+		content.Error = &models.StatusError{Code: resp.StatusCode}
+		content.ErrorCategory = models.CategoryHTTPStatus
+		return content, retryHint{retryable: true}
+	}
+
+	resumed := partial != nil && resp.StatusCode == http.StatusPartialContent
+	if resp.StatusCode != http.StatusOK && !resumed {
+		retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		content.Error = &models.StatusError{Code: resp.StatusCode}
+		content.ErrorCategory = models.CategoryHTTPStatus
+		return content, retryHint{
+			retryable:     hd.retry.isRetryableStatus(resp.StatusCode),
+			retryAfter:    retryAfter,
+			hasRetryAfter: ok,
+		}
+	}
+
+	// A resumed body only has to hold the bytes that are not on disk yet.
+	limit := hd.maxSize
+	if resumed && limit > 0 {
+		limit = max(limit-partial.offset, 1)
+	}
+	if limit > 0 && resp.ContentLength > limit {
+		if partial != nil {
+			partial.discard()
 		}
+		content.Error = fmt.Errorf("%w: Content-Length %d exceeds %d bytes", models.ErrSizeLimit, resp.ContentLength, hd.maxSize)
+		content.ErrorCategory = models.CategorySizeLimit
+		return content, retryHint{}
 	}
 
-	data, err := io.ReadAll(resp.Body)
+	body := countBody(resp, limit)
+	switch {
+	case partial != nil:
+		content.Body, content.Size, err = partial.receive(url, resp)
+	case hd.stream:
+		content.Body, content.Size, err = spool(hd.spoolDir, resp.Body)
+	default:
+		content.Data, err = io.ReadAll(resp.Body)
+		content.Size = int64(len(content.Data))
+	}
+	content.BytesRead = body.n
 	if err != nil {
-		return Content{
-			URL:      url,
-			Error:    fmt.Errorf("read failed: %v", err),
-			Duration: time.Since(start).Milliseconds(),
+		if partial != nil && errors.Is(err, models.ErrSizeLimit) {
+			partial.discard()
 		}
+		failed := failure(url, "read failed", err, models.CategoryBodyRead)
+		failed.StatusCode = content.StatusCode
+		failed.Header = content.Header
+		failed.FinalURL = content.FinalURL
+		failed.Redirects = content.Redirects
+		failed.Proto = content.Proto
+		failed.TLS = content.TLS
+		failed.BytesRead = content.BytesRead
+		return failed, retryHint{retryable: isRetryableError(ctx, err)}
 	}
+	return content, retryHint{}
+}
 
-	duration := time.Since(start).Milliseconds()
-	if duration == 0 {
-		duration = 1
+// Do sends req the way downloads are sent: with the configured global and per-host headers and netrc
+// credentials, which are moved over to each host redirected to. It is meant for fetching the documents URLs
+// are read from, such as sitemaps, and applies neither retries nor host limits.
+//
+// Parameters:
+//   - req: The request to send.
+//
+// Returns:
+//   - The response, or an error as returned by http.Client.Do.
+func (hd *HTTPDownloader) Do(req *http.Request) (*http.Response, error) {
+	hd.authorize(req, nil)
+	return hd.clientFor(nil).Do(req)
+}
+
+// authorize sets the configured headers for req's host, then the headers of the URL record, and netrc
+// credentials unless req already carries an Authorization header.
+func (hd *HTTPDownloader) authorize(req *http.Request, record map[string]string) {
+	hd.headers.apply(req)
+	for name, value := range record {
+		req.Header.Set(name, value)
+	}
+	if hd.netrc != nil {
+		hd.netrc.apply(req)
 	}
+}
+
+// clientFor returns a copy of the downloader's client, sharing its transport and jar, that moves per-host
+// headers over to each host redirected to and keeps the headers of the URL record on every hop.
+func (hd *HTTPDownloader) clientFor(record map[string]string) *http.Client {
+	client := *hd.client
+	client.CheckRedirect = hd.redirectPolicy(record)
+	return &client
+}
 
+// responseContent returns a Content describing resp, without its payload.
+//
+// Parameters:
+//   - url: The requested URL.
+//   - resp: The response received for it.
+//
+// Returns:
+//   - A Content with the response status, headers, final URL and redirect chain, protocol, TLS details,
+//     and cache validators.
+func responseContent(url string, resp *http.Response) Content {
 	return Content{
-		URL:      url,
-		Data:     data,
-		Duration: duration,
+		URL:          url,
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header,
+		FinalURL:     resp.Request.URL.String(),
+		Redirects:    redirectChain(resp),
+		Proto:        resp.Proto,
+		TLS:          tlsInfo(resp.TLS),
+		ContentType:  resp.Header.Get("Content-Type"),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
 	}
 }
@@ -2,6 +2,8 @@ package downloader
 
 import (
 	"context"
+	"errors"
+	"jfrog-assignment/internal/models"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -57,9 +59,9 @@ func TestHTTPDownloader_Execute(t *testing.T) {
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
-			inputChan := make(chan interface{}, 1)
-			outputChan := make(chan interface{}, 1)
-			inputChan <- tt.url
+			inputChan := make(chan models.URLRecord, 1)
+			outputChan := make(chan Content, 1)
+			inputChan <- models.URLRecord{URL: tt.url}
 			close(inputChan)
 
 			done := make(chan error)
@@ -73,11 +75,7 @@ func TestHTTPDownloader_Execute(t *testing.T) {
 				t.Errorf("Execute failed unexpectedly: %v", err)
 			}
 
-			c, ok := content.(Content)
-			if !ok {
-				t.Fatalf("expected Content type, got %T", content)
-			}
-
+			c := content
 			if tt.expectErr && c.Error == nil {
 				t.Errorf("expected error, got nil")
 			}
@@ -102,10 +100,10 @@ func TestHTTPDownloader_Cancel(t *testing.T) {
 	hd := New()
 
 	ctx, cancel := context.WithCancel(context.Background())
-	inputChan := make(chan interface{}, 1)
-	outputChan := make(chan interface{}, 1)
+	inputChan := make(chan models.URLRecord, 1)
+	outputChan := make(chan Content, 1)
 
-	inputChan <- "http://example.com"
+	inputChan <- models.URLRecord{URL: "http://example.com"}
 	cancel()
 
 	done := make(chan error)
@@ -119,3 +117,60 @@ func TestHTTPDownloader_Cancel(t *testing.T) {
 	}
 	close(inputChan)
 }
+
+func TestHTTPDownloader_RecordHeaders(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("test content"))
+	}))
+	defer ts.Close()
+
+	rec := models.URLRecord{URL: ts.URL, Headers: map[string]string{"X-Api-Key": "secret"}, Subdir: "docs"}
+	c := New().fetch(context.Background(), rec, logger)
+	if c.Error != nil {
+		t.Fatalf("unexpected error: %v", c.Error)
+	}
+	if c.Record.Subdir != "docs" {
+		t.Errorf("expected record to be carried on the content, got %+v", c.Record)
+	}
+}
+
+func TestHTTPDownloader_ResponseMetadata(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Build", "42")
+		w.Write([]byte("test content"))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	c := New().fetch(context.Background(), models.URLRecord{URL: ts.URL + "/old"}, logger)
+	if c.Error != nil {
+		t.Fatalf("unexpected error: %v", c.Error)
+	}
+	if c.StatusCode != http.StatusOK || c.FinalURL != ts.URL+"/new" || c.Header.Get("X-Build") != "42" {
+		t.Errorf("unexpected response metadata: status %d, final URL %s, headers %v", c.StatusCode, c.FinalURL, c.Header)
+	}
+	if c.Proto != "HTTP/1.1" || c.TLS != nil || c.BytesRead != int64(len("test content")) || c.ErrorCategory != "" {
+		t.Errorf("unexpected connection metadata: proto %s, TLS %v, %d bytes read, category %q", c.Proto, c.TLS, c.BytesRead, c.ErrorCategory)
+	}
+
+	c = New().fetch(context.Background(), models.URLRecord{URL: ts.URL + "/missing"}, logger)
+	if c.Error == nil || c.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404 on failed content, got %d, %v", c.StatusCode, c.Error)
+	}
+	var statusErr *models.StatusError
+	if !errors.As(c.Error, &statusErr) || statusErr.Code != http.StatusNotFound {
+		t.Errorf("expected a StatusError with code 404, got %v", c.Error)
+	}
+}
@@ -0,0 +1,149 @@
+package downloader
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"jfrog-assignment/internal/models"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// classifyError returns the category of a transport or read error, and the sentinel error describing it.
+//
+// Parameters:
+//   - err: The error returned by the HTTP client or while reading a body.
+//   - fallback: The category to use when err matches no more specific one.
+//
+// Returns:
+//   - The error category.
+//   - The matching models sentinel error, or nil if there is none.
+func classifyError(err error, fallback models.ErrorCategory) (models.ErrorCategory, error) {
+	if errors.Is(err, context.Canceled) {
+		return models.CategoryCanceled, models.ErrCanceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return models.CategoryTimeout, models.ErrTimeout
+	}
+	if errors.Is(err, models.ErrSizeLimit) {
+		return models.CategorySizeLimit, models.ErrSizeLimit
+	}
+	if errors.Is(err, models.ErrRedirect) {
+		return models.CategoryRedirect, models.ErrRedirect
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return models.CategoryDNS, models.ErrDNS
+	}
+
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		hostnameErr      x509.HostnameError
+		invalidCert      x509.CertificateInvalidError
+		recordHeaderErr  tls.RecordHeaderError
+		certVerifyErr    *tls.CertificateVerificationError
+		alertErr         tls.AlertError
+	)
+	if errors.As(err, &unknownAuthority) || errors.As(err, &hostnameErr) || errors.As(err, &invalidCert) ||
+		errors.As(err, &recordHeaderErr) || errors.As(err, &certVerifyErr) || errors.As(err, &alertErr) {
+		return models.CategoryTLS, models.ErrTLS
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return models.CategoryTimeout, models.ErrTimeout
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return models.CategoryConnection, models.ErrConnRefused
+	}
+	if errors.Is(err, syscall.ECONNRESET) {
+		return models.CategoryConnection, nil
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return models.CategoryConnection, nil
+	}
+	return fallback, nil
+}
+
+// failure returns a Content reporting that downloading url failed with err.
+// The error is wrapped with the sentinel of its category, so both can be tested with errors.Is.
+//
+// Parameters:
+//   - url: The URL being downloaded.
+//   - msg: What was being done when err occurred.
+//   - err: The underlying error.
+//   - fallback: The category to use when err matches no more specific one.
+//
+// Returns:
+//   - A Content with Error and ErrorCategory set.
+func failure(url, msg string, err error, fallback models.ErrorCategory) Content {
+	category, sentinel := classifyError(err, fallback)
+	if sentinel != nil && !errors.Is(err, sentinel) {
+		err = fmt.Errorf("%w: %w", sentinel, err)
+	}
+	return Content{
+		URL:           url,
+		Error:         fmt.Errorf("%s: %w", msg, err),
+		ErrorCategory: category,
+	}
+}
+
+// tlsInfo describes the TLS connection state of a response.
+//
+// Returns:
+//   - The TLS details, or nil if state is nil because the response was received over plain HTTP.
+func tlsInfo(state *tls.ConnectionState) *models.TLSInfo {
+	if state == nil {
+		return nil
+	}
+	info := &models.TLSInfo{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+	}
+	if len(state.PeerCertificates) > 0 {
+		leaf := state.PeerCertificates[0]
+		info.PeerSubject = leaf.Subject.String()
+		info.PeerIssuer = leaf.Issuer.String()
+		info.PeerNotAfter = leaf.NotAfter
+	}
+	return info
+}
+
+// countingBody counts the bytes read from a response body and fails once they exceed a limit.
+type countingBody struct {
+	io.ReadCloser       // Underlying response body
+	n             int64 // Bytes read so far
+	limit         int64 // Maximum number of bytes to read; 0 for no limit
+}
+
+// Read reads from the underlying body and counts the bytes returned.
+// It fails with models.ErrSizeLimit as soon as more than limit bytes have been read.
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	if b.limit > 0 && b.n > b.limit {
+		return n, fmt.Errorf("%w: body exceeds %d bytes", models.ErrSizeLimit, b.limit)
+	}
+	return n, err
+}
+
+// countBody replaces the body of resp with one that counts the bytes read from it.
+//
+// Parameters:
+//   - resp: The response whose body is read.
+//   - limit: Maximum number of bytes that may be read; 0 for no limit.
+//
+// Returns:
+//   - The counting body now set on resp.
+func countBody(resp *http.Response, limit int64) *countingBody {
+	body := &countingBody{ReadCloser: resp.Body, limit: limit}
+	resp.Body = body
+	return body
+}
@@ -0,0 +1,150 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"jfrog-assignment/internal/models"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		want     models.ErrorCategory
+		sentinel error
+	}{
+		{"canceled", fmt.Errorf("get: %w", context.Canceled), models.CategoryCanceled, models.ErrCanceled},
+		{"deadline", context.DeadlineExceeded, models.CategoryTimeout, models.ErrTimeout},
+		{"net timeout", &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}, models.CategoryTimeout, models.ErrTimeout},
+		{"dns", &net.DNSError{Err: "no such host", Name: "nonexistent.invalid", IsNotFound: true}, models.CategoryDNS, models.ErrDNS},
+		{"refused", &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, models.CategoryConnection, models.ErrConnRefused},
+		{"reset", &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, models.CategoryConnection, nil},
+		{"size limit", fmt.Errorf("%w: body exceeds 10 bytes", models.ErrSizeLimit), models.CategorySizeLimit, models.ErrSizeLimit},
+		{"unknown", errors.New("unexpected EOF"), models.CategoryBodyRead, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, sentinel := classifyError(tt.err, models.CategoryBodyRead)
+			if got != tt.want || sentinel != tt.sentinel {
+				t.Errorf("expected %q (%v), got %q (%v)", tt.want, tt.sentinel, got, sentinel)
+			}
+		})
+	}
+}
+
+func TestFailure_WrapsSentinelAndCause(t *testing.T) {
+	cause := &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	c := failure("http://example.com", "download failed", cause, models.CategoryConnection)
+
+	var opErr *net.OpError
+	if !errors.Is(c.Error, models.ErrConnRefused) || !errors.As(c.Error, &opErr) {
+		t.Errorf("expected error wrapping both the sentinel and the cause, got %v", c.Error)
+	}
+	if c.ErrorCategory != models.CategoryConnection {
+		t.Errorf("expected category %q, got %q", models.CategoryConnection, c.ErrorCategory)
+	}
+}
+
+func TestHTTPDownloader_ErrorCategory(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("test content"))
+	}))
+	defer tlsServer.Close()
+
+	// Reserve a port, then close the listener so that connecting to it is refused.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedURL := "http://" + ln.Addr().String()
+	ln.Close()
+
+	tests := []struct {
+		name     string
+		url      string
+		want     models.ErrorCategory
+		sentinel error
+	}{
+		{"http status", ts.URL, models.CategoryHTTPStatus, nil},
+		{"untrusted certificate", tlsServer.URL, models.CategoryTLS, models.ErrTLS},
+		{"connection refused", closedURL, models.CategoryConnection, models.ErrConnRefused},
+		{"invalid URL", "http://[::1", models.CategoryRequest, nil},
+	}
+
+	hd := New(WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := hd.fetch(context.Background(), models.URLRecord{URL: tt.url}, logger)
+			if c.Error == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if c.ErrorCategory != tt.want {
+				t.Errorf("expected category %q, got %q (%v)", tt.want, c.ErrorCategory, c.Error)
+			}
+			if tt.sentinel != nil && !errors.Is(c.Error, tt.sentinel) {
+				t.Errorf("expected error wrapping %v, got %v", tt.sentinel, c.Error)
+			}
+		})
+	}
+}
+
+func TestHTTPDownloader_MaxSize(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	payload := strings.Repeat("x", 100)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked" {
+			// Flushing before writing the body makes the server omit Content-Length.
+			w.(http.Flusher).Flush()
+		}
+		w.Write([]byte(payload))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		maxSize int64
+		wantErr bool
+	}{
+		{"within limit", "/", 100, false},
+		{"Content-Length over limit", "/", 99, true},
+		{"body over limit", "/chunked", 99, true},
+		{"unlimited", "/chunked", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hd := New(WithMaxSize(tt.maxSize))
+			c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL + tt.path}, logger)
+			if !tt.wantErr {
+				if c.Error != nil || string(c.Data) != payload {
+					t.Errorf("expected full payload, got %d bytes, %v", len(c.Data), c.Error)
+				}
+				return
+			}
+			if !errors.Is(c.Error, models.ErrSizeLimit) || c.ErrorCategory != models.CategorySizeLimit {
+				t.Errorf("expected size limit error, got %q: %v", c.ErrorCategory, c.Error)
+			}
+			if c.Attempts != 1 {
+				t.Errorf("expected size limit errors not to be retried, got %d attempts", c.Attempts)
+			}
+		})
+	}
+}
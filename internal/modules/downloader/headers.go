@@ -0,0 +1,97 @@
+package downloader
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RequestHeaders holds headers added to every request and per-host additions.
+// Per-host headers override global headers of the same name, and the headers of a URL record override both.
+// Header values often carry credentials, so they are never logged.
+type RequestHeaders struct {
+	Global map[string]string            `json:"global"` // Headers sent to every host
+	Hosts  map[string]map[string]string `json:"hosts"`  // Headers keyed by host name or host:port
+}
+
+// apply sets the headers that apply to req's host on req.
+func (h RequestHeaders) apply(req *http.Request) {
+	for name, value := range h.Global {
+		req.Header.Set(name, value)
+	}
+	for name, value := range h.forHost(strings.ToLower(req.URL.Host)) {
+		req.Header.Set(name, value)
+	}
+}
+
+// reapply moves the per-host headers of req, a redirect from a chain started at originHost, over to req's host.
+// http.Client copies the headers of the first request onto every hop, so the headers set for originHost fall
+// back to their global value or are removed, and the headers set for req's host are added; credentials
+// configured for one host never follow a redirect to another. Headers of the URL record are kept.
+//
+// Parameters:
+//   - req: The request about to be sent for the redirect.
+//   - originHost: Host (host or host:port) of the first request of the redirect chain.
+//   - record: Headers of the URL record; may be nil.
+func (h RequestHeaders) reapply(req *http.Request, originHost string, record map[string]string) {
+	prev, next := h.forHost(strings.ToLower(originHost)), h.forHost(strings.ToLower(req.URL.Host))
+	if len(prev) == 0 && len(next) == 0 {
+		return
+	}
+	fromRecord := make(map[string]bool, len(record))
+	for name := range record {
+		fromRecord[http.CanonicalHeaderKey(name)] = true
+	}
+
+	for name := range prev {
+		if fromRecord[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		if value, ok := h.lookupGlobal(name); ok {
+			req.Header.Set(name, value)
+		} else {
+			req.Header.Del(name)
+		}
+	}
+	for name, value := range next {
+		if !fromRecord[http.CanonicalHeaderKey(name)] {
+			req.Header.Set(name, value)
+		}
+	}
+}
+
+// lookupGlobal returns the value of the global header named name, matched case-insensitively like HTTP headers.
+func (h RequestHeaders) lookupGlobal(name string) (string, bool) {
+	for global, value := range h.Global {
+		if strings.EqualFold(global, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// forHost returns the per-host headers for the given URL host (host or host:port).
+func (h RequestHeaders) forHost(host string) map[string]string {
+	if headers, ok := h.Hosts[host]; ok {
+		return headers
+	}
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.HasSuffix(host, "]") {
+		return h.Hosts[host[:i]]
+	}
+	return nil
+}
+
+// ParseHeader parses a header written as "Name: value".
+//
+// Parameters:
+//   - value: The header line.
+//
+// Returns:
+//   - The header name and value, and false if value has no colon or an empty name.
+func ParseHeader(value string) (string, string, bool) {
+	name, val, ok := strings.Cut(value, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(val), true
+}
@@ -0,0 +1,181 @@
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// headerEcho responds with the request headers as JSON.
+func headerEcho() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(r.Header)
+	})
+}
+
+// sentHeaders decodes the headers echoed by headerEcho.
+func sentHeaders(t *testing.T, c Content) http.Header {
+	t.Helper()
+	if c.Error != nil {
+		t.Fatalf("unexpected error: %v", c.Error)
+	}
+	var header http.Header
+	if err := json.Unmarshal(c.Data, &header); err != nil {
+		t.Fatal(err)
+	}
+	return header
+}
+
+func TestHTTPDownloader_Headers(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	ts := httptest.NewServer(headerEcho())
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+	hostname, _, _ := strings.Cut(host, ":")
+
+	tests := []struct {
+		name    string
+		headers RequestHeaders
+		record  map[string]string
+		want    map[string]string
+	}{
+		{
+			name:    "global",
+			headers: RequestHeaders{Global: map[string]string{"X-Api-Key": "global"}},
+			want:    map[string]string{"X-Api-Key": "global"},
+		},
+		{
+			name: "host overrides global",
+			headers: RequestHeaders{
+				Global: map[string]string{"X-Api-Key": "global", "X-Team": "core"},
+				Hosts:  map[string]map[string]string{host: {"X-Api-Key": "host"}},
+			},
+			want: map[string]string{"X-Api-Key": "host", "X-Team": "core"},
+		},
+		{
+			name:    "host name without port",
+			headers: RequestHeaders{Hosts: map[string]map[string]string{hostname: {"Authorization": "Bearer token"}}},
+			want:    map[string]string{"Authorization": "Bearer token"},
+		},
+		{
+			name:    "other host",
+			headers: RequestHeaders{Hosts: map[string]map[string]string{"example.com": {"Authorization": "Bearer token"}}},
+			want:    map[string]string{"Authorization": ""},
+		},
+		{
+			name:    "record overrides configured headers",
+			headers: RequestHeaders{Global: map[string]string{"X-Api-Key": "global"}},
+			record:  map[string]string{"X-Api-Key": "record"},
+			want:    map[string]string{"X-Api-Key": "record"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hd := New(WithHeaders(tt.headers))
+			c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL, Headers: tt.record}, logger)
+			header := sentHeaders(t, c)
+			for name, want := range tt.want {
+				if got := header.Get(name); got != want {
+					t.Errorf("expected %s %q, got %q", name, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPDownloader_HeadersAcrossRedirects(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	target := httptest.NewServer(headerEcho())
+	defer target.Close()
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer origin.Close()
+	// Reach the origin as localhost, so that the redirect to 127.0.0.1 changes host.
+	originURL := strings.Replace(origin.URL, "127.0.0.1", "localhost", 1)
+	targetHost := strings.TrimPrefix(target.URL, "http://")
+
+	hd := New(WithHeaders(RequestHeaders{
+		Global: map[string]string{"X-Team": "core"},
+		Hosts: map[string]map[string]string{
+			"localhost": {"X-Api-Key": "origin-secret", "X-Team": "origin", "X-Trace": "origin"},
+			targetHost:  {"X-Api-Key": "target-key"},
+		},
+	}))
+	c := hd.fetch(context.Background(), models.URLRecord{URL: originURL, Headers: map[string]string{"X-Trace": "record"}}, logger)
+	header := sentHeaders(t, c)
+
+	want := map[string]string{"X-Api-Key": "target-key", "X-Team": "core", "X-Trace": "record"}
+	for name, value := range want {
+		if got := header.Get(name); got != value {
+			t.Errorf("expected %s %q at the target, got %q", name, value, got)
+		}
+	}
+	if len(c.Redirects) != 1 {
+		t.Errorf("expected one redirect, got %+v", c.Redirects)
+	}
+}
+
+func TestHTTPDownloader_HeadersAcrossTwoRedirects(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	// Each test server listens on its own port, so every hop changes host.
+	last := httptest.NewServer(headerEcho())
+	defer last.Close()
+	middle := httptest.NewServer(http.RedirectHandler(last.URL, http.StatusFound))
+	defer middle.Close()
+	origin := httptest.NewServer(http.RedirectHandler(middle.URL, http.StatusFound))
+	defer origin.Close()
+	host := func(ts *httptest.Server) string { return strings.TrimPrefix(ts.URL, "http://") }
+
+	hd := New(WithHeaders(RequestHeaders{
+		Hosts: map[string]map[string]string{
+			host(origin): {"X-Api-Key": "origin-secret", "X-Origin": "origin"},
+			host(middle): {"X-Middle": "middle"},
+			host(last):   {"X-Last": "last"},
+		},
+	}))
+	c := hd.fetch(context.Background(), models.URLRecord{URL: origin.URL}, logger)
+	header := sentHeaders(t, c)
+
+	for _, name := range []string{"X-Api-Key", "X-Origin", "X-Middle"} {
+		if got := header.Get(name); got != "" {
+			t.Errorf("expected no %s at the last host, got %q", name, got)
+		}
+	}
+	if got := header.Get("X-Last"); got != "last" {
+		t.Errorf("expected X-Last %q at the last host, got %q", "last", got)
+	}
+	if len(c.Redirects) != 2 {
+		t.Errorf("expected two redirects, got %+v", c.Redirects)
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		value string
+		name  string
+		val   string
+		ok    bool
+	}{
+		{value: "Authorization: Bearer a:b", name: "Authorization", val: "Bearer a:b", ok: true},
+		{value: " X-Empty :", name: "X-Empty", ok: true},
+		{value: "no colon"},
+		{value: ": value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			name, val, ok := ParseHeader(tt.value)
+			if name != tt.name || val != tt.val || ok != tt.ok {
+				t.Errorf("expected %q %q %v, got %q %q %v", tt.name, tt.val, tt.ok, name, val, ok)
+			}
+		})
+	}
+}
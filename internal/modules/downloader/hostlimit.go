@@ -0,0 +1,165 @@
+package downloader
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HostLimit caps the load placed on a single host. Zero values mean unlimited.
+type HostLimit struct {
+	RequestsPerSecond float64 `json:"requests_per_second"` // Sustained request rate (token bucket refill rate)
+	Burst             int     `json:"burst"`               // Requests allowed back to back (token bucket size); defaults to 1
+	MaxConcurrency    int     `json:"max_concurrency"`     // Downloads in flight at once
+}
+
+// HostLimits holds the limit applied to every host and per-host overrides.
+// An override replaces the default limit entirely for that host.
+type HostLimits struct {
+	Default HostLimit            `json:"default"` // Limit for hosts without an override
+	Hosts   map[string]HostLimit `json:"hosts"`   // Overrides keyed by host name or host:port
+}
+
+// limitFor returns the limit that applies to the given URL host (host or host:port).
+func (l HostLimits) limitFor(host string) HostLimit {
+	if limit, ok := l.Hosts[host]; ok {
+		return limit
+	}
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.HasSuffix(host, "]") {
+		if limit, ok := l.Hosts[host[:i]]; ok {
+			return limit
+		}
+	}
+	return l.Default
+}
+
+// tokenBucket is a token bucket rate limiter. Tokens may go negative, which queues callers fairly.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64   // Tokens added per second
+	burst  float64   // Maximum number of stored tokens
+	tokens float64   // Currently available tokens
+	last   time.Time // Time of the last refill
+}
+
+// newTokenBucket creates a full token bucket.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait takes a token, blocking until one is available.
+//
+// Returns:
+//   - ctx.Err() if ctx is canceled while waiting, nil otherwise.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+	deficit := -b.tokens
+	b.mu.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+	if !sleep(ctx, time.Duration(deficit/b.rate*float64(time.Second))) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// hostState holds the limiters for a single host.
+type hostState struct {
+	bucket *tokenBucket  // Rate limiter, nil when unlimited
+	slots  chan struct{} // Concurrency semaphore, nil when unlimited
+}
+
+// hostLimiter enforces HostLimits, lazily creating per-host state.
+type hostLimiter struct {
+	limits HostLimits
+	mu     sync.Mutex
+	hosts  map[string]*hostState
+}
+
+// newHostLimiter creates a hostLimiter for the given limits.
+func newHostLimiter(limits HostLimits) *hostLimiter {
+	return &hostLimiter{
+		limits: limits,
+		hosts:  make(map[string]*hostState),
+	}
+}
+
+// state returns the limiter state for a host, creating it on first use.
+func (l *hostLimiter) state(host string) *hostState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if s, ok := l.hosts[host]; ok {
+		return s
+	}
+	limit := l.limits.limitFor(host)
+	s := &hostState{}
+	if limit.RequestsPerSecond > 0 {
+		s.bucket = newTokenBucket(limit.RequestsPerSecond, limit.Burst)
+	}
+	if limit.MaxConcurrency > 0 {
+		s.slots = make(chan struct{}, limit.MaxConcurrency)
+	}
+	l.hosts[host] = s
+	return s
+}
+
+// tryAcquire reserves a concurrency slot for the host of rawURL without waiting.
+//
+// Returns:
+//   - A function releasing the slot and true, or false if every slot of the host is taken.
+func (l *hostLimiter) tryAcquire(rawURL string) (func(), bool) {
+	s := l.state(hostOf(rawURL))
+	if s.slots == nil {
+		return func() {}, true
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return func() { <-s.slots }, true
+	default:
+		return nil, false
+	}
+}
+
+// wait blocks until the host of rawURL may receive another request.
+//
+// Returns:
+//   - ctx.Err() if ctx is canceled while waiting, nil otherwise.
+func (l *hostLimiter) wait(ctx context.Context, rawURL string) error {
+	s := l.state(hostOf(rawURL))
+	if s.bucket == nil {
+		return nil
+	}
+	return s.bucket.wait(ctx)
+}
+
+// hostOf returns the lowercased host (and port, if any) of a URL, tolerating a missing scheme.
+func hostOf(rawURL string) string {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
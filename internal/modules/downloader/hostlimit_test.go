@@ -0,0 +1,172 @@
+package downloader
+
+import (
+	"context"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestHTTPDownloader_HostConcurrency(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	var inFlight, maxInFlight int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("test content"))
+	}))
+	defer ts.Close()
+
+	hd := New(WithHostLimits(HostLimits{Default: HostLimit{MaxConcurrency: 2}}))
+
+	inputChan := make(chan models.URLRecord, 10)
+	outputChan := make(chan Content, 10)
+	for i := 0; i < 10; i++ {
+		inputChan <- models.URLRecord{URL: ts.URL}
+	}
+	close(inputChan)
+
+	if err := hd.Execute(context.Background(), inputChan, outputChan, logger); err != nil {
+		t.Fatalf("Execute failed unexpectedly: %v", err)
+	}
+	close(outputChan)
+
+	for c := range outputChan {
+		if c.Error != nil {
+			t.Errorf("unexpected error: %v", c.Error)
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 concurrent requests, got %d", maxInFlight)
+	}
+}
+
+func TestHTTPDownloader_SaturatedHostDoesNotBlockOthers(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	const fastURLs = 10
+	var served int32
+	fastDone := make(chan struct{})
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&served, 1) == fastURLs {
+			close(fastDone)
+		}
+		w.Write([]byte("fast"))
+	}))
+	defer fast.Close()
+
+	// The slow host only answers once the other host was served, or gives up after a while.
+	var waited int32
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-fastDone:
+		case <-time.After(2 * time.Second):
+			atomic.AddInt32(&waited, 1)
+		}
+		w.Write([]byte("slow"))
+	}))
+	defer slow.Close()
+
+	slowHost := strings.TrimPrefix(slow.URL, "http://")
+	hd := New(WithHostLimits(HostLimits{Hosts: map[string]HostLimit{slowHost: {MaxConcurrency: 1}}}))
+
+	// More slow records than workers, all ahead of the fast ones.
+	records := maxWorkers + 10
+	inputChan := make(chan models.URLRecord, records+fastURLs)
+	outputChan := make(chan Content, records+fastURLs)
+	for i := 0; i < records; i++ {
+		inputChan <- models.URLRecord{URL: slow.URL, Priority: 1}
+	}
+	for i := 0; i < fastURLs; i++ {
+		inputChan <- models.URLRecord{URL: fast.URL}
+	}
+	close(inputChan)
+
+	if err := hd.Execute(context.Background(), inputChan, outputChan, logger); err != nil {
+		t.Fatalf("Execute failed unexpectedly: %v", err)
+	}
+	close(outputChan)
+
+	count := 0
+	for c := range outputChan {
+		count++
+		if c.Error != nil {
+			t.Errorf("unexpected error: %v", c.Error)
+		}
+	}
+	if count != records+fastURLs {
+		t.Errorf("expected %d results, got %d", records+fastURLs, count)
+	}
+	if waited > 0 {
+		t.Errorf("the fast host was held up by the saturated one")
+	}
+}
+
+func TestTokenBucket_Wait(t *testing.T) {
+	bucket := newTokenBucket(100, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := bucket.wait(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// Two tokens are available immediately, the remaining four arrive at 100/s.
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("expected rate limiting to delay requests, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	slow := newTokenBucket(0.001, 1)
+	slow.wait(ctx)
+	if err := slow.wait(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestHostLimits_LimitFor(t *testing.T) {
+	limits := HostLimits{
+		Default: HostLimit{MaxConcurrency: 8},
+		Hosts: map[string]HostLimit{
+			"artifacts.internal":  {MaxConcurrency: 2},
+			"mirror.example:8080": {MaxConcurrency: 4},
+		},
+	}
+
+	tests := []struct {
+		host     string
+		expected int
+	}{
+		{host: "artifacts.internal", expected: 2},
+		{host: "artifacts.internal:443", expected: 2},
+		{host: "mirror.example:8080", expected: 4},
+		{host: "mirror.example", expected: 8},
+		{host: "other.example", expected: 8},
+	}
+
+	for _, tt := range tests {
+		if got := limits.limitFor(tt.host).MaxConcurrency; got != tt.expected {
+			t.Errorf("limitFor(%q): expected concurrency %d, got %d", tt.host, tt.expected, got)
+		}
+	}
+
+	if got := hostOf("Example.COM:8080/path"); got != "example.com:8080" {
+		t.Errorf("expected example.com:8080, got %q", got)
+	}
+}
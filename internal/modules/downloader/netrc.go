@@ -0,0 +1,153 @@
+package downloader
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// netrcLogin is the login and password of one netrc entry.
+type netrcLogin struct {
+	login    string // User name
+	password string // Password or token
+}
+
+// Netrc holds credentials read from a .netrc file, applied as HTTP basic auth to requests for matching hosts.
+type Netrc struct {
+	machines map[string]netrcLogin // Credentials keyed by lowercased machine name
+	fallback *netrcLogin           // Credentials of the "default" entry, if any
+}
+
+// DefaultNetrcPath returns the path of the user's netrc file: $NETRC if set, ~/.netrc otherwise.
+//
+// Returns:
+//   - The path, or an error if the home directory cannot be determined.
+func DefaultNetrcPath() (string, error) {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".netrc"), nil
+}
+
+// LoadNetrc reads the netrc file at path. Entries are "machine <host> login <user> password <secret>",
+// optionally followed by a "default" entry used for every other host; "account" values and "macdef"
+// macros are ignored.
+//
+// Parameters:
+//   - path: Path of the netrc file.
+//
+// Returns:
+//   - The parsed credentials, or an error if the file cannot be read or is malformed.
+func LoadNetrc(path string) (*Netrc, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open netrc: %w", err)
+	}
+	defer file.Close()
+
+	n := &Netrc{machines: make(map[string]netrcLogin)}
+	var (
+		current *netrcLogin // Entry whose login and password are being read
+		machine string      // Machine of current; empty for the default entry
+		inMacro bool        // Whether the lines being read belong to a macdef
+	)
+	finish := func() {
+		if current == nil {
+			return
+		}
+		if machine == "" {
+			n.fallback = current
+		} else if _, ok := n.machines[machine]; !ok {
+			// Like curl, the first entry for a machine wins.
+			n.machines[machine] = *current
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if inMacro {
+			// A macro definition ends at the first empty line.
+			inMacro = strings.TrimSpace(text) != ""
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(text), "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		for i := 0; i < len(fields); i++ {
+			token := fields[i]
+			switch token {
+			case "default":
+				finish()
+				current, machine = &netrcLogin{}, ""
+				continue
+			case "macdef":
+				finish()
+				inMacro = true
+				i = len(fields)
+				continue
+			}
+
+			if i+1 >= len(fields) {
+				return nil, fmt.Errorf("%s:%d: missing value for %q", path, line, token)
+			}
+			i++
+			value := fields[i]
+			switch token {
+			case "machine":
+				finish()
+				current, machine = &netrcLogin{}, strings.ToLower(value)
+			case "login", "password", "account":
+				if current == nil {
+					return nil, fmt.Errorf("%s:%d: %q outside of a machine entry", path, line, token)
+				}
+				if token == "login" {
+					current.login = value
+				} else if token == "password" {
+					current.password = value
+				}
+			default:
+				return nil, fmt.Errorf("%s:%d: unknown netrc token %q", path, line, token)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read netrc: %w", err)
+	}
+	finish()
+	return n, nil
+}
+
+// lookup returns the credentials for host, a host name without port.
+func (n *Netrc) lookup(host string) (netrcLogin, bool) {
+	if login, ok := n.machines[strings.ToLower(host)]; ok {
+		return login, true
+	}
+	if n.fallback != nil {
+		return *n.fallback, true
+	}
+	return netrcLogin{}, false
+}
+
+// apply sets basic auth from the credentials for req's host, unless req already carries
+// an Authorization header or credentials in its URL.
+func (n *Netrc) apply(req *http.Request) {
+	if req.Header.Get("Authorization") != "" || req.URL.User != nil {
+		return
+	}
+	if login, ok := n.lookup(req.URL.Hostname()); ok {
+		req.SetBasicAuth(login.login, login.password)
+	}
+}
@@ -0,0 +1,96 @@
+package downloader
+
+import (
+	"context"
+	"jfrog-assignment/internal/models"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// writeNetrc writes a netrc file with the given content and returns its path.
+func writeNetrc(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".netrc")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadNetrc(t *testing.T) {
+	path := writeNetrc(t, `# internal servers
+machine Artifacts.Internal login ci password s3cret
+machine artifacts.internal login ignored password ignored
+
+machine mirror.example
+  login reader
+  password token account ops
+macdef init
+machine not.a.machine login x password y
+
+default login anonymous password guest
+`)
+
+	n, err := LoadNetrc(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		host     string
+		login    string
+		password string
+	}{
+		{host: "artifacts.internal", login: "ci", password: "s3cret"},
+		{host: "mirror.example", login: "reader", password: "token"},
+		{host: "not.a.machine", login: "anonymous", password: "guest"},
+		{host: "other.example", login: "anonymous", password: "guest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			login, ok := n.lookup(tt.host)
+			if !ok || login.login != tt.login || login.password != tt.password {
+				t.Errorf("expected %s/%s, got %+v (%v)", tt.login, tt.password, login, ok)
+			}
+		})
+	}
+}
+
+func TestLoadNetrc_Malformed(t *testing.T) {
+	for _, content := range []string{
+		"machine host login",
+		"login user password secret",
+		"machine host user x",
+	} {
+		if _, err := LoadNetrc(writeNetrc(t, content)); err == nil {
+			t.Errorf("expected error for %q, got nil", content)
+		}
+	}
+}
+
+func TestHTTPDownloader_Netrc(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	ts := httptest.NewServer(headerEcho())
+	defer ts.Close()
+
+	n, err := LoadNetrc(writeNetrc(t, "machine 127.0.0.1 login ci password s3cret\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hd := New(WithNetrc(n))
+
+	c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL}, logger)
+	if got := sentHeaders(t, c).Get("Authorization"); got != "Basic Y2k6czNjcmV0" {
+		t.Errorf("expected basic auth from netrc, got %q", got)
+	}
+
+	headers := map[string]string{"Authorization": "Bearer token"}
+	c = hd.fetch(context.Background(), models.URLRecord{URL: ts.URL, Headers: headers}, logger)
+	if got := sentHeaders(t, c).Get("Authorization"); got != "Bearer token" {
+		t.Errorf("expected explicit Authorization header to win, got %q", got)
+	}
+}
@@ -0,0 +1,146 @@
+package downloader
+
+import "net/http"
+
+// Option configures an HTTPDownloader.
+type Option func(*HTTPDownloader)
+
+// WithRetryPolicy sets how failed downloads are retried.
+//
+// Parameters:
+//   - policy: The retry policy to apply to every URL.
+//
+// Returns:
+//   - An Option applying the policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(hd *HTTPDownloader) {
+		hd.retry = policy
+	}
+}
+
+// WithClient sets the HTTP client used for every download, e.g. one created with NewClient.
+//
+// Parameters:
+//   - client: The client to share between all workers.
+//
+// Returns:
+//   - An Option applying the client.
+func WithClient(client *http.Client) Option {
+	return func(hd *HTTPDownloader) {
+		hd.client = client
+	}
+}
+
+// WithHeaders sets headers added to every request, or to requests for specific hosts.
+//
+// Parameters:
+//   - headers: Global and per-host headers.
+//
+// Returns:
+//   - An Option applying the headers.
+func WithHeaders(headers RequestHeaders) Option {
+	return func(hd *HTTPDownloader) {
+		hd.headers = headers
+	}
+}
+
+// WithNetrc sends basic auth credentials from a netrc file (see LoadNetrc) to matching hosts,
+// unless a request already carries an Authorization header.
+//
+// Parameters:
+//   - netrc: The credentials.
+//
+// Returns:
+//   - An Option enabling netrc authentication.
+func WithNetrc(netrc *Netrc) Option {
+	return func(hd *HTTPDownloader) {
+		hd.netrc = netrc
+	}
+}
+
+// WithHostLimits sets per-host rate limits and concurrency caps.
+//
+// Parameters:
+//   - limits: The default limit and per-host overrides.
+//
+// Returns:
+//   - An Option applying the limits.
+func WithHostLimits(limits HostLimits) Option {
+	return func(hd *HTTPDownloader) {
+		hd.limiter = newHostLimiter(limits)
+	}
+}
+
+// WithStreaming makes the downloader spool each body to a temporary file instead of memory.
+// Content.Body is then set instead of Content.Data, and the consumer must Close it.
+//
+// Parameters:
+//   - spoolDir: Directory for the temporary files; os.TempDir() if empty.
+//
+// Returns:
+//   - An Option enabling streaming mode.
+func WithStreaming(spoolDir string) Option {
+	return func(hd *HTTPDownloader) {
+		hd.stream = true
+		hd.spoolDir = spoolDir
+	}
+}
+
+// WithResume enables resumable downloads. Bodies are streamed into .partial files in partialDir
+// that survive interruptions, and later attempts or runs resume them with HTTP Range requests.
+// Implies streaming mode.
+//
+// Parameters:
+//   - partialDir: Directory for .partial files and their metadata; must persist between runs.
+//
+// Returns:
+//   - An Option enabling resumable downloads.
+func WithResume(partialDir string) Option {
+	return func(hd *HTTPDownloader) {
+		hd.stream = true
+		hd.resume = true
+		hd.spoolDir = partialDir
+	}
+}
+
+// WithConditionalRequests makes the downloader send If-None-Match and If-Modified-Since for URLs
+// with validators from a previous run. A 304 response yields Content with NotModified set.
+//
+// Parameters:
+//   - src: Source of the validators recorded by earlier runs.
+//
+// Returns:
+//   - An Option enabling conditional requests.
+func WithConditionalRequests(src ValidatorSource) Option {
+	return func(hd *HTTPDownloader) {
+		hd.validators = src
+	}
+}
+
+// WithChecksums sets expected digests from a sidecar checksum file (see LoadChecksumFile).
+// They apply to records without their own checksum, matched by URL, file name or URL path basename.
+//
+// Parameters:
+//   - sums: Expected checksums keyed by URL or file name.
+//
+// Returns:
+//   - An Option enabling sidecar checksum verification.
+func WithChecksums(sums map[string]Checksum) Option {
+	return func(hd *HTTPDownloader) {
+		hd.checksums = sums
+	}
+}
+
+// WithMaxSize limits the size of downloaded bodies. Larger bodies fail with models.ErrSizeLimit,
+// without being retried; a Content-Length above the limit fails before the body is read.
+//
+// Parameters:
+//   - bytes: Maximum body size in bytes; 0 for no limit.
+//
+// Returns:
+//   - An Option applying the limit.
+func WithMaxSize(bytes int64) Option {
+	return func(hd *HTTPDownloader) {
+		hd.maxSize = bytes
+	}
+}
@@ -0,0 +1,56 @@
+package downloader
+
+import (
+	"container/heap"
+	"jfrog-assignment/internal/models"
+)
+
+const maxPending = 10000 // Maximum number of records buffered while waiting for a worker
+
+// queuedRecord is a record waiting for a download worker.
+type queuedRecord struct {
+	record models.URLRecord
+	seq    int // Arrival order, used to keep records of equal priority first-in first-out
+}
+
+// recordQueue is a priority queue of records, highest priority first.
+// It implements heap.Interface; use push, popQueued and requeue rather than the heap methods directly.
+type recordQueue struct {
+	items []queuedRecord
+	seq   int
+}
+
+func (q *recordQueue) Len() int { return len(q.items) }
+
+func (q *recordQueue) Less(i, j int) bool {
+	if q.items[i].record.Priority != q.items[j].record.Priority {
+		return q.items[i].record.Priority > q.items[j].record.Priority
+	}
+	return q.items[i].seq < q.items[j].seq
+}
+
+func (q *recordQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *recordQueue) Push(x any) { q.items = append(q.items, x.(queuedRecord)) }
+
+func (q *recordQueue) Pop() any {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
+
+// push adds a record to the queue.
+func (q *recordQueue) push(rec models.URLRecord) {
+	heap.Push(q, queuedRecord{record: rec, seq: q.seq})
+	q.seq++
+}
+
+// popQueued removes and returns the queued record with the highest priority, keeping its arrival order.
+func (q *recordQueue) popQueued() queuedRecord {
+	return heap.Pop(q).(queuedRecord)
+}
+
+// requeue puts back a record returned by popQueued, ahead of later arrivals of the same priority.
+func (q *recordQueue) requeue(item queuedRecord) {
+	heap.Push(q, item)
+}
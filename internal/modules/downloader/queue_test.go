@@ -0,0 +1,43 @@
+package downloader
+
+import (
+	"jfrog-assignment/internal/models"
+	"testing"
+)
+
+func TestRecordQueue(t *testing.T) {
+	var q recordQueue
+	q.push(models.URLRecord{URL: "low-1"})
+	q.push(models.URLRecord{URL: "high", Priority: 10})
+	q.push(models.URLRecord{URL: "low-2"})
+	q.push(models.URLRecord{URL: "negative", Priority: -1})
+	q.push(models.URLRecord{URL: "mid", Priority: 5})
+
+	expected := []string{"high", "mid", "low-1", "low-2", "negative"}
+	for i, want := range expected {
+		if got := q.popQueued().record.URL; got != want {
+			t.Errorf("pop %d: expected %s, got %s", i, want, got)
+		}
+	}
+	if q.Len() != 0 {
+		t.Errorf("expected empty queue, got %d items", q.Len())
+	}
+}
+
+func TestRecordQueue_Requeue(t *testing.T) {
+	var q recordQueue
+	q.push(models.URLRecord{URL: "first"})
+	q.push(models.URLRecord{URL: "second"})
+
+	// A record put back keeps its place ahead of later arrivals of the same priority.
+	item := q.popQueued()
+	q.push(models.URLRecord{URL: "third"})
+	q.requeue(item)
+
+	expected := []string{"first", "second", "third"}
+	for i, want := range expected {
+		if got := q.popQueued().record.URL; got != want {
+			t.Errorf("pop %d: expected %s, got %s", i, want, got)
+		}
+	}
+}
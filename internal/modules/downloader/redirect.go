@@ -0,0 +1,82 @@
+package downloader
+
+import (
+	"fmt"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const defaultMaxRedirects = 10 // Redirects http.Client follows when it has no CheckRedirect function
+
+// checkRedirect returns the CheckRedirect function of an http.Client enforcing the redirect settings of cfg.
+//
+// Parameters:
+//   - cfg: The client settings holding the redirect limit and restrictions.
+//
+// Returns:
+//   - A function refusing redirects beyond cfg.MaxRedirects, to another host if cfg.DenyCrossHostRedirects
+//     is set, and from HTTPS to HTTP if cfg.DenyHTTPSDowngrade is set, with an error wrapping models.ErrRedirect.
+func checkRedirect(cfg ClientConfig) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > cfg.MaxRedirects {
+			return fmt.Errorf("%w: stopped after %d redirects", models.ErrRedirect, cfg.MaxRedirects)
+		}
+		origin, prev := via[0].URL, via[len(via)-1].URL
+		if cfg.DenyCrossHostRedirects && !strings.EqualFold(req.URL.Hostname(), origin.Hostname()) {
+			return fmt.Errorf("%w: cross-host redirect from %s to %s", models.ErrRedirect, origin.Hostname(), req.URL.Hostname())
+		}
+		if cfg.DenyHTTPSDowngrade && prev.Scheme == "https" && req.URL.Scheme == "http" {
+			return fmt.Errorf("%w: HTTPS to HTTP downgrade to %s", models.ErrRedirect, req.URL)
+		}
+		return nil
+	}
+}
+
+// redirectPolicy returns the CheckRedirect function of a single download: the client's own policy, followed by
+// moving the configured per-host headers and netrc credentials over to the host redirected to.
+//
+// Parameters:
+//   - record: Headers of the URL record, which are kept on every hop; may be nil.
+//
+// Returns:
+//   - A function to set as the CheckRedirect of a copy of the downloader's client.
+func (hd *HTTPDownloader) redirectPolicy(record map[string]string) func(req *http.Request, via []*http.Request) error {
+	check := hd.client.CheckRedirect
+	return func(req *http.Request, via []*http.Request) error {
+		if check != nil {
+			if err := check(req, via); err != nil {
+				return err
+			}
+		} else if len(via) >= defaultMaxRedirects {
+			// Mirror the policy http.Client applies when CheckRedirect is nil.
+			return fmt.Errorf("stopped after %d redirects", defaultMaxRedirects)
+		}
+		hd.headers.reapply(req, via[0].URL.Host, record)
+		if hd.netrc != nil {
+			hd.netrc.apply(req)
+		}
+		return nil
+	}
+}
+
+// redirectChain returns the redirects that led to resp, oldest first.
+func redirectChain(resp *http.Response) []models.Redirect {
+	var chain []models.Redirect
+	for req := resp.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		chain = append(chain, redirectOf(req.Response, req.URL.String()))
+	}
+	// The loop walks from the final response backwards.
+	slices.Reverse(chain)
+	return chain
+}
+
+// redirectOf describes the redirect response resp, which sent the client to location.
+func redirectOf(resp *http.Response, location string) models.Redirect {
+	return models.Redirect{
+		URL:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Location:   location,
+	}
+}
@@ -0,0 +1,117 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestHTTPDownloader_Redirects(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		case "/other-host":
+			// The test server listens on 127.0.0.1, so localhost is another host name for the same server.
+			http.Redirect(w, r, strings.Replace(r.Host, "127.0.0.1", "http://localhost", 1)+"/c", http.StatusFound)
+		default:
+			w.Write([]byte("test content"))
+		}
+	}))
+	defer plain.Close()
+
+	secure := httptest.NewTLSServer(http.RedirectHandler(plain.URL+"/c", http.StatusFound))
+	defer secure.Close()
+
+	tests := []struct {
+		name      string
+		url       string
+		config    func(*ClientConfig)
+		wantErr   bool
+		wantChain []models.Redirect
+	}{
+		{
+			name: "chain recorded",
+			url:  plain.URL + "/a",
+			wantChain: []models.Redirect{
+				{URL: plain.URL + "/a", StatusCode: http.StatusMovedPermanently, Location: plain.URL + "/b"},
+				{URL: plain.URL + "/b", StatusCode: http.StatusFound, Location: plain.URL + "/c"},
+			},
+		},
+		{
+			name:    "too many redirects",
+			url:     plain.URL + "/a",
+			config:  func(cfg *ClientConfig) { cfg.MaxRedirects = 1 },
+			wantErr: true,
+			wantChain: []models.Redirect{
+				{URL: plain.URL + "/a", StatusCode: http.StatusMovedPermanently, Location: plain.URL + "/b"},
+				{URL: plain.URL + "/b", StatusCode: http.StatusFound, Location: plain.URL + "/c"},
+			},
+		},
+		{
+			name: "cross-host allowed",
+			url:  plain.URL + "/other-host",
+			wantChain: []models.Redirect{
+				{URL: plain.URL + "/other-host", StatusCode: http.StatusFound, Location: strings.Replace(plain.URL, "127.0.0.1", "localhost", 1) + "/c"},
+			},
+		},
+		{
+			name:    "cross-host denied",
+			url:     plain.URL + "/other-host",
+			config:  func(cfg *ClientConfig) { cfg.DenyCrossHostRedirects = true },
+			wantErr: true,
+			wantChain: []models.Redirect{
+				{URL: plain.URL + "/other-host", StatusCode: http.StatusFound, Location: strings.Replace(plain.URL, "127.0.0.1", "localhost", 1) + "/c"},
+			},
+		},
+		{
+			name:    "downgrade denied",
+			url:     secure.URL,
+			config:  func(cfg *ClientConfig) { cfg.DenyHTTPSDowngrade = true },
+			wantErr: true,
+			wantChain: []models.Redirect{
+				{URL: secure.URL, StatusCode: http.StatusFound, Location: plain.URL + "/c"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultClientConfig()
+			if tt.config != nil {
+				tt.config(&cfg)
+			}
+			client := NewClient(cfg)
+			trustServer(client, secure)
+			hd := New(WithClient(client), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+			c := hd.fetch(context.Background(), models.URLRecord{URL: tt.url}, logger)
+			if tt.wantErr {
+				if !errors.Is(c.Error, models.ErrRedirect) || c.ErrorCategory != models.CategoryRedirect {
+					t.Errorf("expected refused redirect, got %q: %v", c.ErrorCategory, c.Error)
+				}
+			} else if c.Error != nil {
+				t.Fatalf("unexpected error: %v", c.Error)
+			}
+
+			if len(c.Redirects) != len(tt.wantChain) {
+				t.Fatalf("expected redirect chain %+v, got %+v", tt.wantChain, c.Redirects)
+			}
+			for i, want := range tt.wantChain {
+				if c.Redirects[i] != want {
+					t.Errorf("hop %d: expected %+v, got %+v", i, want, c.Redirects[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package downloader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// partialMeta is the metadata stored next to a .partial file, used to validate a resumed download.
+type partialMeta struct {
+	URL          string `json:"url"`                     // URL the partial body belongs to
+	ETag         string `json:"etag,omitempty"`          // Strong ETag of the response, if any
+	LastModified string `json:"last_modified,omitempty"` // Last-Modified of the response, if any
+}
+
+// validator returns the value to send in If-Range, preferring a strong ETag.
+func (m partialMeta) validator() string {
+	if m.ETag != "" && !strings.HasPrefix(m.ETag, "W/") {
+		return m.ETag
+	}
+	return m.LastModified
+}
+
+// partialFile tracks the on-disk state of a resumable download.
+type partialFile struct {
+	path     string      // Path of the .partial body
+	metaPath string      // Path of the .partial.json metadata
+	meta     partialMeta // Metadata loaded from metaPath
+	offset   int64       // Bytes already downloaded that can be resumed from
+}
+
+// openPartial locates the partial download for rec in dir and determines how much of it can be resumed.
+// A partial file is only resumable if its metadata belongs to rec's URL and carries a validator for If-Range.
+//
+// Parameters:
+//   - dir: Directory holding partial downloads.
+//   - rec: The URL record being downloaded.
+//
+// Returns:
+//   - The partial download state; its offset is zero if nothing can be resumed.
+func openPartial(dir string, rec models.URLRecord) *partialFile {
+	url := rec.URL
+	base := filepath.Join(dir, partialKey(rec)+".partial")
+	p := &partialFile{path: base, metaPath: base + ".json"}
+
+	data, err := os.ReadFile(p.metaPath)
+	if err != nil || json.Unmarshal(data, &p.meta) != nil || p.meta.URL != url || p.meta.validator() == "" {
+		return p
+	}
+	if info, err := os.Stat(p.path); err == nil {
+		p.offset = info.Size()
+	}
+	return p
+}
+
+// partialKey names the partial download of rec. Records of the same URL saved under a different file name or
+// subdirectory are downloaded separately, so they get partial files of their own.
+func partialKey(rec models.URLRecord) string {
+	key := rec.URL
+	if rec.Filename != "" || rec.Subdir != "" {
+		key += "\x00" + rec.Filename + "\x00" + rec.Subdir
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// prepare adds Range and If-Range headers to req when part of the body is already on disk.
+// If the resource changed since, the server ignores the range and sends the full body.
+func (p *partialFile) prepare(req *http.Request) {
+	if p.offset == 0 {
+		return
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", p.offset))
+	req.Header.Set("If-Range", p.meta.validator())
+}
+
+// receive writes resp's body to the partial file, appending to it for a 206 response and
+// starting over otherwise. The partial file is kept on failure so a later attempt can resume.
+//
+// Parameters:
+//   - url: The URL being downloaded.
+//   - resp: A 200 or 206 response.
+//
+// Returns:
+//   - The complete body, whose Close removes the partial file and its metadata, the total size,
+//     and an error if the body could not be written.
+func (p *partialFile) receive(url string, resp *http.Response) (*spoolFile, int64, error) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	offset := int64(0)
+	meta := partialMeta{
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+
+	if resp.StatusCode == http.StatusPartialContent {
+		start, ok := parseContentRangeStart(resp.Header.Get("Content-Range"))
+		if !ok || start != p.offset {
+			p.discard()
+			return nil, 0, fmt.Errorf("unexpected Content-Range %q for resume at byte %d", resp.Header.Get("Content-Range"), p.offset)
+		}
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		offset = p.offset
+		if meta.validator() == "" {
+			meta = p.meta
+		}
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := os.WriteFile(p.metaPath, data, 0644); err != nil {
+		return nil, 0, fmt.Errorf("write partial metadata: %w", err)
+	}
+
+	file, err := os.OpenFile(p.path, flags, 0644)
+	if err != nil {
+		return nil, 0, fmt.Errorf("open partial file: %w", err)
+	}
+	n, err := io.Copy(file, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, offset + n, err
+	}
+
+	body, err := os.Open(p.path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("reopen partial file: %w", err)
+	}
+	return &spoolFile{file: body, meta: p.metaPath}, offset + n, nil
+}
+
+// discard removes the partial file and its metadata so the next attempt starts from scratch.
+func (p *partialFile) discard() {
+	os.Remove(p.path)
+	os.Remove(p.metaPath)
+	p.offset = 0
+}
+
+// parseContentRangeStart extracts the first byte position from a "bytes start-end/size" header.
+func parseContentRangeStart(value string) (int64, bool) {
+	spec, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,208 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// rangeServer serves payload with ETag and Range support, recording the Range header of each request.
+func rangeServer(t *testing.T, payload, etag string, ranges *[]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(payload))
+	}))
+}
+
+// writePartial stores the first n bytes of payload as a partial download for url.
+func writePartial(t *testing.T, dir, url, payload string, n int, etag string) {
+	t.Helper()
+	p := openPartial(dir, models.URLRecord{URL: url})
+	if err := os.WriteFile(p.path, []byte(payload[:n]), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(partialMeta{URL: url, ETag: etag})
+	if err := os.WriteFile(p.metaPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readBody(t *testing.T, c Content) string {
+	t.Helper()
+	if c.Error != nil {
+		t.Fatalf("unexpected error: %v", c.Error)
+	}
+	defer c.Body.Close()
+	data, err := io.ReadAll(c.Body)
+	if err != nil {
+		t.Fatalf("reading body failed: %v", err)
+	}
+	return string(data)
+}
+
+func TestHTTPDownloader_ResumeFromPartial(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	payload := strings.Repeat("abcdefghij", 1000)
+
+	tests := []struct {
+		name        string
+		partialETag string
+		serverETag  string
+		expectRange bool
+	}{
+		{name: "matching ETag resumes", partialETag: `"v1"`, serverETag: `"v1"`, expectRange: true},
+		{name: "changed ETag restarts", partialETag: `"v0"`, serverETag: `"v1"`, expectRange: true},
+		{name: "weak ETag is not resumed", partialETag: `W/"v1"`, serverETag: `W/"v1"`, expectRange: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ranges []string
+			ts := rangeServer(t, payload, tt.serverETag, &ranges)
+			defer ts.Close()
+
+			dir := t.TempDir()
+			writePartial(t, dir, ts.URL, payload, 4000, tt.partialETag)
+
+			hd := New(WithResume(dir))
+			c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL}, logger)
+			if got := readBody(t, c); got != payload {
+				t.Errorf("expected full payload (%d bytes), got %d bytes", len(payload), len(got))
+			}
+			if c.Size != int64(len(payload)) {
+				t.Errorf("expected size %d, got %d", len(payload), c.Size)
+			}
+			if tt.expectRange && (len(ranges) != 1 || ranges[0] != "bytes=4000-") {
+				t.Errorf("expected a single resumed request, got ranges %q", ranges)
+			}
+			if !tt.expectRange && (len(ranges) != 1 || ranges[0] != "") {
+				t.Errorf("expected a single full request, got ranges %q", ranges)
+			}
+
+			entries, _ := os.ReadDir(dir)
+			if len(entries) != 0 {
+				t.Errorf("expected partial files to be removed after Close, found %d entries", len(entries))
+			}
+		})
+	}
+}
+
+func TestHTTPDownloader_ResumeAfterInterruption(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	payload := strings.Repeat("0123456789", 1000)
+
+	var calls int32
+	var ranges []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", `"v1"`)
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// Send half of the body, then drop the connection.
+			w.Header().Set("Content-Length", "10000")
+			w.Write([]byte(payload[:5000]))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(payload))
+	}))
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	hd := New(WithResume(t.TempDir()), WithRetryPolicy(policy))
+
+	c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL}, logger)
+	if got := readBody(t, c); got != payload {
+		t.Errorf("expected full payload, got %d bytes", len(got))
+	}
+	if c.Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", c.Attempts)
+	}
+	if c.Size != 10000 || c.BytesRead != 5000 {
+		t.Errorf("expected size 10000 with 5000 bytes read by the last attempt, got %d and %d", c.Size, c.BytesRead)
+	}
+	if len(ranges) != 2 || ranges[1] != "bytes=5000-" {
+		t.Errorf("expected second request to resume at byte 5000, got ranges %q", ranges)
+	}
+}
+
+func TestHTTPDownloader_ResumeUnsupported(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	payload := strings.Repeat("x", 2000)
+
+	// A server that ignores Range and always sends the full body.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		io.Copy(w, bytes.NewReader([]byte(payload)))
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	writePartial(t, dir, ts.URL, payload, 500, `"v1"`)
+
+	hd := New(WithResume(dir))
+	c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL}, logger)
+	if got := readBody(t, c); got != payload {
+		t.Errorf("expected full payload without duplication, got %d bytes", len(got))
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.partial*")); len(files) != 0 {
+		t.Errorf("expected partial files to be removed after Close, found %v", files)
+	}
+}
+
+func TestOpenPartial_Key(t *testing.T) {
+	dir := t.TempDir()
+	url := "http://example.com/app.jar"
+
+	plain := openPartial(dir, models.URLRecord{URL: url})
+	named := openPartial(dir, models.URLRecord{URL: url, Filename: "app-1.0.jar"})
+	inSubdir := openPartial(dir, models.URLRecord{URL: url, Subdir: "mirror"})
+	paths := map[string]bool{plain.path: true, named.path: true, inSubdir.path: true}
+	if len(paths) != 3 {
+		t.Errorf("expected separate partial files per file name and subdirectory, got %v", paths)
+	}
+
+	// A partial download of one target is not resumed for another.
+	writePartial(t, dir, url, "0123456789", 5, `"v1"`)
+	if p := openPartial(dir, models.URLRecord{URL: url, Filename: "app-1.0.jar"}); p.offset != 0 {
+		t.Errorf("expected nothing to resume for another file name, got offset %d", p.offset)
+	}
+	if p := openPartial(dir, models.URLRecord{URL: url}); p.offset != 5 {
+		t.Errorf("expected to resume from 5, got offset %d", p.offset)
+	}
+}
+
+func TestParseContentRangeStart(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int64
+		ok       bool
+	}{
+		{value: "bytes 100-199/200", expected: 100, ok: true},
+		{value: "bytes 0-0/*", expected: 0, ok: true},
+		{value: "bytes */200", ok: false},
+		{value: "items 1-2/3", ok: false},
+		{value: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseContentRangeStart(tt.value)
+		if ok != tt.ok || got != tt.expected {
+			t.Errorf("parseContentRangeStart(%q) = %d, %v; expected %d, %v", tt.value, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package downloader
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"jfrog-assignment/internal/models"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed downloads are retried.
+type RetryPolicy struct {
+	MaxAttempts     int           // Total attempts per URL, including the first one
+	BaseDelay       time.Duration // Delay before the first retry, doubled for each subsequent retry
+	MaxDelay        time.Duration // Upper bound for a single delay; 0 for no bound
+	Jitter          float64       // Fraction (0-1) of each delay that is randomized
+	RetryableStatus []int         // HTTP status codes that trigger a retry
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured:
+// three attempts with exponential backoff from 500ms up to 10s, retrying 429, 502, 503 and 504.
+//
+// Returns:
+//   - The default RetryPolicy.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.5,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// attempts returns the number of attempts to make, never less than one.
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay before the given retry (1 for the first retry).
+// The delay grows exponentially from BaseDelay, is capped at MaxDelay unless it is 0, and up to Jitter of it is randomized.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || delay < p.MaxDelay) && delay <= math.MaxInt64/2; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	jitter := p.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	if jitter > 0 && delay > 0 {
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+	return delay
+}
+
+// isRetryableStatus reports whether the HTTP status code should be retried.
+func (p RetryPolicy) isRetryableStatus(code int) bool {
+	for _, c := range p.RetryableStatus {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryableError reports whether a transport error is likely transient.
+// Cancellation of ctx, oversized bodies, refused redirects, unknown hosts and certificate problems are permanent;
+// other network errors, including client and response header timeouts, are retried.
+func isRetryableError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, models.ErrSizeLimit) || errors.Is(err, models.ErrRedirect) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false
+	}
+
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		hostnameErr      x509.HostnameError
+		invalidCert      x509.CertificateInvalidError
+	)
+	if errors.As(err, &unknownAuthority) || errors.As(err, &hostnameErr) || errors.As(err, &invalidCert) {
+		return false
+	}
+	return true
+}
+
+// parseRetryAfter interprets a Retry-After header given either in seconds or as an HTTP date.
+//
+// Parameters:
+//   - value: The header value.
+//   - now: The current time, used to convert HTTP dates into a delay.
+//
+// Returns:
+//   - The requested delay and true, or false if the header is absent or malformed.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
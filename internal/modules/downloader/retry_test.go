@@ -0,0 +1,189 @@
+package downloader
+
+import (
+	"context"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestHTTPDownloader_Retry(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	tests := []struct {
+		name           string
+		failures       int32
+		status         int
+		retryAfter     string
+		maxAttempts    int
+		expectErr      bool
+		expectAttempts int
+	}{
+		{
+			name:           "recovers from transient 503",
+			failures:       2,
+			status:         http.StatusServiceUnavailable,
+			maxAttempts:    3,
+			expectErr:      false,
+			expectAttempts: 3,
+		},
+		{
+			name:           "gives up after max attempts",
+			failures:       5,
+			status:         http.StatusBadGateway,
+			maxAttempts:    2,
+			expectErr:      true,
+			expectAttempts: 2,
+		},
+		{
+			name:           "does not retry non-retryable status",
+			failures:       1,
+			status:         http.StatusNotFound,
+			maxAttempts:    3,
+			expectErr:      true,
+			expectAttempts: 1,
+		},
+		{
+			name:           "honors short Retry-After",
+			failures:       1,
+			status:         http.StatusTooManyRequests,
+			retryAfter:     "0",
+			maxAttempts:    3,
+			expectErr:      false,
+			expectAttempts: 2,
+		},
+		{
+			name:           "gives up when Retry-After exceeds max delay",
+			failures:       1,
+			status:         http.StatusTooManyRequests,
+			retryAfter:     "3600",
+			maxAttempts:    3,
+			expectErr:      true,
+			expectAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) <= tt.failures {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Write([]byte("test content"))
+			}))
+			defer ts.Close()
+
+			policy := DefaultRetryPolicy()
+			policy.MaxAttempts = tt.maxAttempts
+			policy.BaseDelay = time.Millisecond
+			policy.MaxDelay = 10 * time.Millisecond
+			hd := New(WithRetryPolicy(policy))
+
+			c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL}, logger)
+			if tt.expectErr && c.Error == nil {
+				t.Errorf("expected error, got nil")
+			}
+			if !tt.expectErr && c.Error != nil {
+				t.Errorf("unexpected error: %v", c.Error)
+			}
+			if c.Attempts != tt.expectAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.expectAttempts, c.Attempts)
+			}
+		})
+	}
+}
+
+func TestHTTPDownloader_RetriesTimeout(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// Hang until the client gives up on the first attempt.
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte("test content"))
+	}))
+	defer ts.Close()
+
+	cfg := DefaultClientConfig()
+	cfg.Timeout = 50 * time.Millisecond
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	hd := New(WithClient(NewClient(cfg)), WithRetryPolicy(policy))
+
+	c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL}, logger)
+	if c.Error != nil || c.Attempts != 2 {
+		t.Errorf("expected success on the second attempt, got %d attempts, error %v", c.Attempts, c.Error)
+	}
+
+	// A canceled run is not retried, whatever the error says.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c = hd.fetch(ctx, models.URLRecord{URL: ts.URL}, logger)
+	if c.Error == nil || c.Attempts > 1 {
+		t.Errorf("expected a single failed attempt after cancellation, got %d attempts, error %v", c.Attempts, c.Error)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, want := range expected {
+		if got := policy.backoff(i + 1); got != want {
+			t.Errorf("retry %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+
+	// Without MaxDelay the delay keeps doubling.
+	unbounded := RetryPolicy{BaseDelay: 100 * time.Millisecond}
+	if got := unbounded.backoff(6); got != 3200*time.Millisecond {
+		t.Errorf("unbounded retry 6: expected 3.2s, got %v", got)
+	}
+	if got := unbounded.backoff(1000); got <= 0 {
+		t.Errorf("unbounded retry 1000: expected a positive delay, got %v", got)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("jittered delay %v outside [50ms, 100ms]", got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{value: "", ok: false},
+		{value: "120", expected: 2 * time.Minute, ok: true},
+		{value: "-1", ok: false},
+		{value: "Mon, 01 Jan 2024 00:00:30 GMT", expected: 30 * time.Second, ok: true},
+		{value: "Sun, 31 Dec 2023 23:59:00 GMT", expected: 0, ok: true},
+		{value: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.ok || got != tt.expected {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; expected %v, %v", tt.value, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
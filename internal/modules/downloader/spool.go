@@ -0,0 +1,67 @@
+package downloader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// spoolFile is a temporary file holding a downloaded body. Closing it removes the file.
+type spoolFile struct {
+	file *os.File
+	meta string // Metadata file removed along with the body, if any
+}
+
+// spool copies r into a new temporary file in dir and rewinds it for reading.
+// Memory use is bounded by the copy buffer regardless of the body size.
+//
+// Parameters:
+//   - dir: Directory for the temporary file; os.TempDir() if empty.
+//   - r: The body to copy.
+//
+// Returns:
+//   - The spooled body, the number of bytes written, and an error if writing fails.
+func spool(dir string, r io.Reader) (*spoolFile, int64, error) {
+	file, err := os.CreateTemp(dir, "urldownloader-*.spool")
+	if err != nil {
+		return nil, 0, fmt.Errorf("create spool file: %w", err)
+	}
+	sf := &spoolFile{file: file}
+
+	n, err := io.Copy(file, r)
+	if err != nil {
+		sf.Close()
+		return nil, n, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		sf.Close()
+		return nil, n, fmt.Errorf("rewind spool file: %w", err)
+	}
+	return sf, n, nil
+}
+
+// Read reads from the spooled body.
+func (f *spoolFile) Read(p []byte) (int, error) {
+	return f.file.Read(p)
+}
+
+// Close closes and removes the spool file.
+func (f *spoolFile) Close() error {
+	closeErr := f.file.Close()
+	removeErr := os.Remove(f.file.Name())
+	if errors.Is(removeErr, os.ErrNotExist) {
+		removeErr = nil
+	}
+	if f.meta != "" {
+		if err := os.Remove(f.meta); err != nil && !errors.Is(err, os.ErrNotExist) {
+			removeErr = errors.Join(removeErr, err)
+		}
+	}
+	return errors.Join(closeErr, removeErr)
+}
+
+// Seek sets the read offset of the spooled body, so it can be verified and then read again.
+func (f *spoolFile) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
@@ -0,0 +1,78 @@
+package downloader
+
+import (
+	"context"
+	"io"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestHTTPDownloader_Streaming(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	payload := strings.Repeat("0123456789", 100000)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(payload))
+	}))
+	defer ts.Close()
+
+	spoolDir := t.TempDir()
+	hd := New(WithStreaming(spoolDir))
+
+	c := hd.fetch(context.Background(), models.URLRecord{URL: ts.URL}, logger)
+	if c.Error != nil {
+		t.Fatalf("unexpected error: %v", c.Error)
+	}
+	if c.Data != nil {
+		t.Errorf("expected no buffered data in streaming mode, got %d bytes", len(c.Data))
+	}
+	if c.Body == nil {
+		t.Fatalf("expected streamed body, got nil")
+	}
+	if c.Size != int64(len(payload)) {
+		t.Errorf("expected size %d, got %d", len(payload), c.Size)
+	}
+
+	data, err := io.ReadAll(c.Body)
+	if err != nil {
+		t.Fatalf("reading body failed: %v", err)
+	}
+	if string(data) != payload {
+		t.Errorf("streamed body does not match payload")
+	}
+
+	if err := c.Body.Close(); err != nil {
+		t.Errorf("closing body failed: %v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(spoolDir, "*"))
+	if len(files) != 0 {
+		t.Errorf("expected spool file to be removed, found %v", files)
+	}
+}
+
+func TestSpool_ReadError(t *testing.T) {
+	spoolDir := t.TempDir()
+
+	_, _, err := spool(spoolDir, io.MultiReader(strings.NewReader("partial"), errReader{}))
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	entries, _ := os.ReadDir(spoolDir)
+	if len(entries) != 0 {
+		t.Errorf("expected spool file to be removed after failure, found %d entries", len(entries))
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
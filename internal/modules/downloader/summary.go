@@ -0,0 +1,104 @@
+package downloader
+
+import (
+	"cmp"
+	"jfrog-assignment/internal/models"
+	"slices"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// failureSummary counts failed downloads by error category, by host and by input file. It is safe for concurrent use.
+type failureSummary struct {
+	mu         sync.Mutex                   // Guards the counters
+	byCategory map[models.ErrorCategory]int // Failures per error category
+	byHost     map[string]int               // Failures per host
+	bySource   map[string]int               // Failures per input file, for records that know theirs
+}
+
+// newFailureSummary creates an empty failureSummary.
+func newFailureSummary() *failureSummary {
+	return &failureSummary{
+		byCategory: make(map[models.ErrorCategory]int),
+		byHost:     make(map[string]int),
+		bySource:   make(map[string]int),
+	}
+}
+
+// add counts c, which must have failed, under its error category, host and input file.
+func (s *failureSummary) add(c Content) {
+	category := c.ErrorCategory
+	if category == "" {
+		category = models.CategoryRequest
+	}
+	host := hostOf(c.URL)
+	if host == "" {
+		host = "(invalid)"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byCategory[category]++
+	s.byHost[host]++
+	if source := c.Record.Source; source != "" {
+		s.bySource[source]++
+	}
+}
+
+// log writes the summary, most frequent categories, hosts and input files first. Nothing is logged if no
+// download failed.
+func (s *failureSummary) log(logger *zap.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.byCategory) == 0 {
+		return
+	}
+	fields := []zap.Field{
+		zap.Array("by_category", sortedCounts(s.byCategory)),
+		zap.Array("by_host", sortedCounts(s.byHost)),
+	}
+	if len(s.bySource) > 0 {
+		fields = append(fields, zap.Array("by_source", sortedCounts(s.bySource)))
+	}
+	logger.Warn("download failures", fields...)
+}
+
+// count is a failure count under one key of a failureSummary.
+type count struct {
+	key string // Error category, host or input file
+	n   int    // Number of failures
+}
+
+// counts is a list of counts that can be logged as an array of {"key": n} objects.
+type counts []count
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (cs counts) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, c := range cs {
+		err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddInt(c.key, c.n)
+			return nil
+		}))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedCounts returns the entries of m ordered by descending count, then by key.
+func sortedCounts[K ~string](m map[K]int) counts {
+	cs := make(counts, 0, len(m))
+	for key, n := range m {
+		cs = append(cs, count{key: string(key), n: n})
+	}
+	slices.SortFunc(cs, func(a, b count) int {
+		if c := cmp.Compare(b.n, a.n); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.key, b.key)
+	})
+	return cs
+}
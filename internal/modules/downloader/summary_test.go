@@ -0,0 +1,43 @@
+package downloader
+
+import (
+	"fmt"
+	"jfrog-assignment/internal/models"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFailureSummary(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(core)
+
+	s := newFailureSummary()
+	s.log(logger)
+	if logs.Len() != 0 {
+		t.Fatalf("expected nothing logged without failures, got %v", logs.All())
+	}
+
+	s.add(Content{URL: "http://a.example.com/1", ErrorCategory: models.CategoryTimeout, Record: models.URLRecord{Source: "b.csv", Line: 2}})
+	s.add(Content{URL: "http://a.example.com/2", ErrorCategory: models.CategoryHTTPStatus, Record: models.URLRecord{Source: "a.csv", Line: 9}})
+	s.add(Content{URL: "http://b.example.com/1", ErrorCategory: models.CategoryTimeout})
+	s.add(Content{URL: "http://[::1", ErrorCategory: models.CategoryRequest})
+	s.log(logger)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one summary entry, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if got, want := fmt.Sprint(fields["by_category"]), "[map[timeout:2] map[http_status:1] map[request:1]]"; got != want {
+		t.Errorf("expected categories %s, got %s", want, got)
+	}
+	if got, want := fmt.Sprint(fields["by_host"]), "[map[a.example.com:2] map[(invalid):1] map[b.example.com:1]]"; got != want {
+		t.Errorf("expected hosts %s, got %s", want, got)
+	}
+	if got, want := fmt.Sprint(fields["by_source"]), "[map[a.csv:1] map[b.csv:1]]"; got != want {
+		t.Errorf("expected sources %s, got %s", want, got)
+	}
+}
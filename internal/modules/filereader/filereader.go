@@ -1,74 +1,353 @@
 package filereader
 
 import (
-	"bufio"
 	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/pipeline"
 	"os"
+	"strconv"
 	"strings"
 
 	"go.uber.org/zap"
 )
 
-// FileReader implements both URLReader and pipeline.Stage for reading URLs from a CSV file.
+// FileReader implements both URLReader and pipeline.Stage for reading URLs from a CSV, text, JSON, NDJSON
+// or YAML file, optionally gzip or zstd compressed. The path Stdin reads from standard input instead.
 type FileReader struct {
-	csvPath string // Path to the CSV file containing URLs
+	path      string    // Path to the input file, or Stdin
+	stdin     io.Reader // Standard input, read when path is Stdin
+	format    Format    // Input format; detected from the file extension if empty
+	urlColumn string    // Name or zero-based index of the URL column; the first column if empty
+	delimiter rune      // Field delimiter
+	comment   rune      // Comment character, or 0 if comments are disabled
+	header    bool      // Whether the first record is a header row
+	columns   Columns
+
+	urlPointer   string // JSON pointer to the URL within a JSON, NDJSON or YAML item
+	itemsPointer string // JSON pointer to the list of items within a JSON or YAML document; the root if empty
+}
+
+// Columns names the optional CSV columns holding per-row download options, by header name
+// (case-insensitive) or zero-based index. Columns missing from the file are ignored.
+type Columns struct {
+	Filename string // Target file name
+	Checksum string // Expected digest, e.g. "sha256:<hex>"
+	Headers  string // Extra request headers as "Name: value; Other: value"
+	Priority string // Integer priority; higher values are downloaded first
+	Subdir   string // Output subdirectory
 }
 
-// New creates a new FileReader instance with the specified CSV file path.
+// DefaultColumns returns the column names used unless WithColumns is given:
+// filename, checksum, headers, priority and subdir.
+//
+// Returns:
+//   - The default Columns.
+func DefaultColumns() Columns {
+	return Columns{
+		Filename: "filename",
+		Checksum: "checksum",
+		Headers:  "headers",
+		Priority: "priority",
+		Subdir:   "subdir",
+	}
+}
+
+// columnIndexes holds the resolved position of every column, or -1 for absent optional columns.
+type columnIndexes struct {
+	url, filename, checksum, headers, priority, subdir int
+}
+
+// Stdin is the input path that reads from standard input.
+const Stdin = "-"
+
+// stdinSource is the Source of records read from standard input.
+const stdinSource = "stdin"
+
+var _ pipeline.Stage[struct{}, models.URLRecord] = (*FileReader)(nil)
+
+// New creates a new FileReader instance reading the given file.
 //
 // Parameters:
-//   - csvPath: The path to the CSV file to read URLs from.
+//   - path: The path to the file to read URLs from, or Stdin.
+//   - opts: Optional settings; by default the format is detected from the file extension, CSV files have a
+//     comma-delimited header row and URLs in the first column, and structured items hold their URL in "url".
 //
 // Returns:
 //   - A pointer to a new FileReader instance.
-func New(csvPath string) *FileReader {
-	return &FileReader{csvPath: csvPath}
+func New(path string, opts ...Option) *FileReader {
+	fr := &FileReader{
+		path:       path,
+		stdin:      os.Stdin,
+		delimiter:  ',',
+		header:     true,
+		columns:    DefaultColumns(),
+		urlPointer: DefaultURLPointer,
+	}
+	for _, opt := range opts {
+		opt(fr)
+	}
+	return fr
 }
 
-// Execute reads URLs from the CSV file and sends them to the output channel as part of the pipeline.
+// Execute reads URL records from the file and sends them to the output channel as part of the pipeline.
+// Each record carries the file and line it was read from. Malformed rows and items are logged with their
+// line number and skipped.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeouts.
 //   - input: Input channel (unused, FileReader generates its own data).
-//   - output: Channel to send URLs to as interface{}.
+//   - output: Channel to send URL records to.
 //   - logger: Logger for logging progress and errors.
 //
 // Returns:
-//   - An error if reading fails, nil otherwise.
-func (fr *FileReader) Execute(ctx context.Context, input <-chan interface{}, output chan<- interface{}, logger *zap.Logger) error {
-	file, err := os.Open(fr.csvPath)
+//   - An error if the file cannot be read or parsed, or the URL column cannot be resolved, nil otherwise.
+func (fr *FileReader) Execute(ctx context.Context, input <-chan struct{}, output chan<- models.URLRecord, logger *zap.Logger) error {
+	source, file := fr.path, fr.stdin
+	if fr.path == Stdin {
+		source = stdinSource
+	} else {
+		f, err := os.Open(fr.path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	r, err := openInput(file)
 	if err != nil {
-		return err
+		return fmt.Errorf("%s: %w", source, err)
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	isHeader := true
-	urlCount := 0
-
-	for scanner.Scan() {
-		select {
-		case <-ctx.Done():
-			logger.Warn("file reading interrupted", zap.Error(ctx.Err()))
-			return ctx.Err()
-		default:
-			if isHeader {
-				isHeader = false
-				continue
-			}
-			url := strings.TrimSpace(scanner.Text())
-			if url != "" {
-				logger.Debug("read URL", zap.String("url", url))
-				output <- url // Send as interface{}
-				urlCount++
-			}
-		}
+	defer r.Close()
+
+	format := fr.format
+	if format == "" {
+		format = DetectFormat(fr.path)
 	}
+	sink := &recordSink{ctx: ctx, output: output, logger: logger, source: source}
 
-	if err := scanner.Err(); err != nil {
+	switch format {
+	case FormatText:
+		err = readText(r, sink)
+	case FormatNDJSON:
+		err = fr.readNDJSON(r, sink)
+	case FormatJSON:
+		err = fr.readJSON(r, sink)
+	case FormatYAML:
+		err = fr.readYAML(r, sink)
+	default:
+		err = fr.readCSV(r, sink)
+	}
+	if err != nil {
 		return err
 	}
 
-	logger.Info("finished reading URLs", zap.Int("total_urls", urlCount))
+	logger.Info("finished reading URLs",
+		zap.String("file", source),
+		zap.String("format", string(format)),
+		zap.Int("total_urls", sink.urls),
+		zap.Int("malformed_rows", sink.malformed))
 	return nil
 }
+
+// recordSink sends the records read from one input to the pipeline and counts them.
+type recordSink struct {
+	ctx       context.Context
+	output    chan<- models.URLRecord
+	logger    *zap.Logger
+	source    string // Input file, or "stdin"
+	urls      int    // Records sent
+	malformed int    // Rows or items skipped
+}
+
+// emit records where rec was read from and sends it to the pipeline, unless it has no URL.
+//
+// Parameters:
+//   - line: Line the record starts on, or 0 if unknown.
+//   - rec: The record.
+//
+// Returns:
+//   - ctx.Err() if reading was canceled, nil otherwise.
+func (s *recordSink) emit(line int, rec models.URLRecord) error {
+	if err := s.ctx.Err(); err != nil {
+		s.logger.Warn("file reading interrupted", zap.Error(err))
+		return err
+	}
+	if rec.URL == "" {
+		return nil
+	}
+	rec.Source, rec.Line = s.source, line
+	s.logger.Debug("read URL", zap.String("url", rec.URL), zap.String("source", rec.Origin()))
+	s.output <- rec
+	s.urls++
+	return nil
+}
+
+// skip logs a malformed row or item and counts it.
+//
+// Parameters:
+//   - line: Line the row or item starts on, or 0 if unknown.
+//   - err: Why it is malformed.
+func (s *recordSink) skip(line int, err error) {
+	fields := []zap.Field{zap.String("file", s.source), zap.Error(err)}
+	if line > 0 {
+		fields = append(fields, zap.Int("line", line))
+	}
+	s.logger.Warn("skipping malformed row", fields...)
+	s.malformed++
+}
+
+// readCSV reads records from CSV rows.
+func (fr *FileReader) readCSV(r io.Reader, sink *recordSink) error {
+	reader := fr.newCSVReader(r)
+	columns, err := fr.resolveColumns(reader)
+	if err != nil {
+		return err
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			sink.skip(parseErr.StartLine, parseErr.Err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := reader.FieldPos(0)
+		rec, err := columns.parse(record)
+		if err != nil {
+			sink.skip(line, err)
+			continue
+		}
+		if err := sink.emit(line, rec); err != nil {
+			return err
+		}
+	}
+}
+
+// newCSVReader creates a CSV reader over r with the configured dialect.
+func (fr *FileReader) newCSVReader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = fr.delimiter
+	reader.Comment = fr.comment
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return reader
+}
+
+// resolveColumns consumes the header row, if any, and locates the URL and option columns.
+func (fr *FileReader) resolveColumns(reader *csv.Reader) (columnIndexes, error) {
+	var header []string
+	if fr.header {
+		record, err := reader.Read()
+		if err != nil && err != io.EOF {
+			return columnIndexes{}, fmt.Errorf("read header: %w", err)
+		}
+		header = record
+	}
+
+	cols := columnIndexes{
+		filename: findColumn(header, fr.columns.Filename),
+		checksum: findColumn(header, fr.columns.Checksum),
+		headers:  findColumn(header, fr.columns.Headers),
+		priority: findColumn(header, fr.columns.Priority),
+		subdir:   findColumn(header, fr.columns.Subdir),
+	}
+
+	if fr.urlColumn == "" {
+		return cols, nil
+	}
+	cols.url = findColumn(header, fr.urlColumn)
+	if cols.url >= 0 {
+		return cols, nil
+	}
+	if !fr.header {
+		return cols, fmt.Errorf("URL column %q must be an index when the file has no header", fr.urlColumn)
+	}
+	return cols, fmt.Errorf("URL column %q not found in header %q", fr.urlColumn, header)
+}
+
+// findColumn returns the index of the column named by spec, matching header names case-insensitively
+// before treating spec as a zero-based index. It returns -1 if spec is empty or matches nothing.
+func findColumn(header []string, spec string) int {
+	if spec == "" {
+		return -1
+	}
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), spec) {
+			return i
+		}
+	}
+	if index, err := strconv.Atoi(spec); err == nil && index >= 0 {
+		return index
+	}
+	return -1
+}
+
+// parse builds a URL record from a CSV row.
+//
+// Returns:
+//   - The record, or an error if the URL column is missing or an option value is invalid.
+func (c columnIndexes) parse(row []string) (models.URLRecord, error) {
+	if c.url >= len(row) {
+		return models.URLRecord{}, fmt.Errorf("missing URL column %d, row has %d fields", c.url, len(row))
+	}
+	field := func(index int) string {
+		if index < 0 || index >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[index])
+	}
+
+	rec := models.URLRecord{
+		URL:      field(c.url),
+		Filename: field(c.filename),
+		Checksum: field(c.checksum),
+		Subdir:   field(c.subdir),
+	}
+
+	if value := field(c.priority); value != "" {
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			return rec, fmt.Errorf("invalid priority %q", value)
+		}
+		rec.Priority = priority
+	}
+
+	if value := field(c.headers); value != "" {
+		headers, err := parseHeaders(value)
+		if err != nil {
+			return rec, err
+		}
+		rec.Headers = headers
+	}
+	return rec, nil
+}
+
+// parseHeaders parses request headers written as "Name: value; Other: value".
+func parseHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, val, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q, expected \"Name: value\"", part)
+		}
+		headers[name] = strings.TrimSpace(val)
+	}
+	return headers, nil
+}
@@ -2,6 +2,7 @@ package filereader
 
 import (
 	"context"
+	"jfrog-assignment/internal/models"
 	"os"
 	"testing"
 
@@ -15,6 +16,7 @@ func TestFileReader_Execute(t *testing.T) {
 	tests := []struct {
 		name         string
 		csvContent   string
+		opts         []Option
 		expectedURLs []string
 		expectErr    bool
 	}{
@@ -30,6 +32,47 @@ func TestFileReader_Execute(t *testing.T) {
 			expectedURLs: []string{},
 			expectErr:    false,
 		},
+		{
+			name:         "BOM and CRLF line endings",
+			csvContent:   "\xEF\xBB\xBFUrls\r\nwww.google.com\r\nwww.yahoo.com\r\n",
+			opts:         []Option{WithURLColumn("urls")},
+			expectedURLs: []string{"www.google.com", "www.yahoo.com"},
+			expectErr:    false,
+		},
+		{
+			name:         "quoted fields and extra columns",
+			csvContent:   "id,url,note\n1,\"http://example.com/a,b\",\"first, quoted\"\n2,http://test.com,plain\n",
+			opts:         []Option{WithURLColumn("url")},
+			expectedURLs: []string{"http://example.com/a,b", "http://test.com"},
+			expectErr:    false,
+		},
+		{
+			name:         "column by index without header",
+			csvContent:   "1;http://example.com\n2;http://test.com\n",
+			opts:         []Option{WithHeader(false), WithDelimiter(';'), WithURLColumn("1")},
+			expectedURLs: []string{"http://example.com", "http://test.com"},
+			expectErr:    false,
+		},
+		{
+			name:         "comment lines",
+			csvContent:   "Urls\n# staging mirror\nhttp://example.com\n",
+			opts:         []Option{WithComment('#')},
+			expectedURLs: []string{"http://example.com"},
+			expectErr:    false,
+		},
+		{
+			name:         "malformed rows are skipped",
+			csvContent:   "id,url\n1,http://example.com\n2\n3,\"http://bad\"quote\n4,http://test.com\n",
+			opts:         []Option{WithURLColumn("url")},
+			expectedURLs: []string{"http://example.com", "http://test.com"},
+			expectErr:    false,
+		},
+		{
+			name:       "unknown column",
+			csvContent: "Urls\nhttp://example.com\n",
+			opts:       []Option{WithURLColumn("link")},
+			expectErr:  true,
+		},
 		{
 			name:       "invalid file",
 			csvContent: "",
@@ -55,9 +98,9 @@ func TestFileReader_Execute(t *testing.T) {
 				filename = "nonexistent.csv"
 			}
 
-			fr := New(filename)
-			inputChan := make(chan interface{}, 1)
-			outputChan := make(chan interface{}, 10)
+			fr := New(filename, tt.opts...)
+			inputChan := make(chan struct{})
+			outputChan := make(chan models.URLRecord, 10)
 			close(inputChan)
 
 			ctx := context.Background()
@@ -65,10 +108,8 @@ func TestFileReader_Execute(t *testing.T) {
 			done := make(chan struct{})
 			go func() {
 				defer close(done)
-				for url := range outputChan {
-					if u, ok := url.(string); ok {
-						urls = append(urls, u)
-					}
+				for rec := range outputChan {
+					urls = append(urls, rec.URL)
 				}
 			}()
 
@@ -93,3 +134,52 @@ func TestFileReader_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestFileReader_OptionColumns(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	csvContent := "url,filename,checksum,headers,priority,subdir\n" +
+		"http://example.com/a,a.bin,sha256:abcd,\"Authorization: Bearer x; X-Trace: 1\",5,nightly/a\n" +
+		"http://example.com/b,,,,,\n" +
+		"http://example.com/c,,,,high,\n" +
+		"http://example.com/d,,,bad-header,,\n"
+
+	tmpFile, err := os.CreateTemp("", "test*.csv")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.WriteString(csvContent)
+	tmpFile.Close()
+
+	inputChan := make(chan struct{})
+	outputChan := make(chan models.URLRecord, 10)
+	close(inputChan)
+
+	if err := New(tmpFile.Name()).Execute(context.Background(), inputChan, outputChan, logger); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(outputChan)
+
+	var records []models.URLRecord
+	for rec := range outputChan {
+		records = append(records, rec)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records (invalid priority and header rows skipped), got %d: %+v", len(records), records)
+	}
+
+	a := records[0]
+	if a.URL != "http://example.com/a" || a.Filename != "a.bin" || a.Checksum != "sha256:abcd" ||
+		a.Priority != 5 || a.Subdir != "nightly/a" {
+		t.Errorf("unexpected record %+v", a)
+	}
+	if a.Headers["Authorization"] != "Bearer x" || a.Headers["X-Trace"] != "1" {
+		t.Errorf("unexpected headers %v", a.Headers)
+	}
+
+	b := records[1]
+	if b.URL != "http://example.com/b" || b.Filename != "" || b.Headers != nil || b.Priority != 0 {
+		t.Errorf("expected record without options, got %+v", b)
+	}
+}
@@ -0,0 +1,127 @@
+package filereader
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"jfrog-assignment/internal/models"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Format is the layout of an input file.
+type Format string
+
+const (
+	FormatCSV    Format = "csv"    // Delimited rows, optionally with a header naming the URL and option columns
+	FormatText   Format = "text"   // One URL per line; blank lines and lines starting with # are ignored
+	FormatJSON   Format = "json"   // A JSON document holding an array of items
+	FormatNDJSON Format = "ndjson" // One JSON item per line
+	FormatYAML   Format = "yaml"   // One or more YAML documents, each holding a list of items
+)
+
+// DefaultURLPointer is the JSON pointer to the URL within a JSON, NDJSON or YAML item, unless WithURLPointer is given.
+const DefaultURLPointer = "/url"
+
+var (
+	utf8BOM   = []byte{0xEF, 0xBB, 0xBF}       // Byte order mark some editors prepend to UTF-8 files
+	gzipMagic = []byte{0x1F, 0x8B}             // First bytes of a gzip stream
+	zstdMagic = []byte{0x28, 0xB5, 0x2F, 0xFD} // First bytes of a zstd frame
+)
+
+// ParseFormat parses the name of an input format.
+//
+// Parameters:
+//   - value: "csv", "text", "json", "ndjson" or "yaml"; "txt", "jsonl" and "yml" are accepted as aliases.
+//
+// Returns:
+//   - The Format, or an error if value is not a known format.
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(value) {
+	case "csv":
+		return FormatCSV, nil
+	case "text", "txt":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	case "ndjson", "jsonl":
+		return FormatNDJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("unknown input format %q (expected csv, text, json, ndjson or yaml)", value)
+	}
+}
+
+// DetectFormat infers the format of a file from its extension, ignoring a trailing .gz, .zst or .zstd.
+// Files with any other extension are read as CSV.
+//
+// Parameters:
+//   - path: Path of the input file.
+//
+// Returns:
+//   - The detected Format.
+func DetectFormat(path string) Format {
+	name := strings.ToLower(filepath.Base(path))
+	for _, suffix := range []string{".gz", ".zst", ".zstd"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	switch filepath.Ext(name) {
+	case ".txt", ".list":
+		return FormatText
+	case ".json":
+		return FormatJSON
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatCSV
+	}
+}
+
+// openInput wraps r in a decompressor if it starts with a gzip or zstd header, and skips a leading UTF-8 BOM.
+//
+// Parameters:
+//   - r: The raw input.
+//
+// Returns:
+//   - The decoded input, which must be closed to release the decompressor, or an error if the
+//     compressed header is invalid.
+func openInput(r io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+	prefix, err := buffered.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	source, closer := buffered, io.NopCloser(nil)
+	switch {
+	case bytes.HasPrefix(prefix, gzipMagic):
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		source, closer = bufio.NewReader(gz), gz
+	case bytes.HasPrefix(prefix, zstdMagic):
+		zr, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+		decoded := zr.IOReadCloser()
+		source, closer = bufio.NewReader(decoded), decoded
+	}
+	return models.ReadCloser{Reader: skipBOM(source), Closer: closer}, nil
+}
+
+// skipBOM discards a leading UTF-8 byte order mark from r.
+func skipBOM(r *bufio.Reader) io.Reader {
+	if prefix, _ := r.Peek(len(utf8BOM)); bytes.Equal(prefix, utf8BOM) {
+		r.Discard(len(utf8BOM))
+	}
+	return r
+}
@@ -0,0 +1,189 @@
+package filereader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/pipeline"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// writeInput writes data to a file with the given name in a temporary directory and returns its path.
+func writeInput(t *testing.T, name string, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	return path
+}
+
+// collect runs fr and returns the records it sent.
+func collect(t *testing.T, fr *FileReader, logger *zap.Logger) ([]models.URLRecord, error) {
+	t.Helper()
+	return run(fr, logger)
+}
+
+// collectMulti runs mr and returns the records it sent.
+func collectMulti(t *testing.T, mr *MultiReader) ([]models.URLRecord, error) {
+	t.Helper()
+	return run(mr, zaptest.NewLogger(t))
+}
+
+// run executes a reading stage and returns the records it sent.
+func run(stage pipeline.Stage[struct{}, models.URLRecord], logger *zap.Logger) ([]models.URLRecord, error) {
+	input := make(chan struct{})
+	close(input)
+	output := make(chan models.URLRecord, 100)
+	err := stage.Execute(context.Background(), input, output, logger)
+	close(output)
+
+	var records []models.URLRecord
+	for rec := range output {
+		records = append(records, rec)
+	}
+	return records, err
+}
+
+// readRecords writes data to a file with the given name and reads it with a FileReader.
+func readRecords(t *testing.T, name string, data []byte, opts ...Option) ([]models.URLRecord, error) {
+	t.Helper()
+	return collect(t, New(writeInput(t, name, string(data)), opts...), zaptest.NewLogger(t))
+}
+
+// urlsOf returns the URLs of records.
+func urlsOf(records []models.URLRecord) []string {
+	urls := make([]string, len(records))
+	for i, rec := range records {
+		urls[i] = rec.URL
+	}
+	return urls
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  Format
+		expectErr bool
+	}{
+		{value: "csv", expected: FormatCSV},
+		{value: "txt", expected: FormatText},
+		{value: "JSON", expected: FormatJSON},
+		{value: "jsonl", expected: FormatNDJSON},
+		{value: "yml", expected: FormatYAML},
+		{value: "xml", expectErr: true},
+		{value: "tsv", expectErr: true}, // Tab-separated files are read as csv with a tab delimiter
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.value)
+		if got != tt.expected || (err != nil) != tt.expectErr {
+			t.Errorf("ParseFormat(%q) = %q, %v; expected %q, error %v", tt.value, got, err, tt.expected, tt.expectErr)
+		}
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected Format
+	}{
+		{path: "urls.csv", expected: FormatCSV},
+		{path: "urls.tsv", expected: FormatCSV},
+		{path: "urls", expected: FormatCSV},
+		{path: "urls.txt", expected: FormatText},
+		{path: "mirrors.list.gz", expected: FormatText},
+		{path: "dir.d/urls.JSON", expected: FormatJSON},
+		{path: "urls.jsonl.zst", expected: FormatNDJSON},
+		{path: "urls.ndjson", expected: FormatNDJSON},
+		{path: "manifest.yml.zstd", expected: FormatYAML},
+		{path: "urls.csv.gz", expected: FormatCSV},
+	}
+
+	for _, tt := range tests {
+		if got := DetectFormat(tt.path); got != tt.expected {
+			t.Errorf("DetectFormat(%q) = %q, expected %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestFileReader_Text(t *testing.T) {
+	data := "# mirrors\n\nhttp://example.com/a\n  http://example.com/b  \r\n#http://example.com/skipped\nhttp://example.com/c"
+
+	records, err := readRecords(t, "urls.txt", []byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"http://example.com/a", "http://example.com/b", "http://example.com/c"}
+	if got := urlsOf(records); !equalStrings(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestFileReader_Compressed(t *testing.T) {
+	text := []byte("http://example.com/a\nhttp://example.com/b\n")
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write(text)
+	gw.Close()
+
+	var zst bytes.Buffer
+	zw, err := zstd.NewWriter(&zst)
+	if err != nil {
+		t.Fatalf("failed to create zstd writer: %v", err)
+	}
+	zw.Write(text)
+	zw.Close()
+
+	tests := []struct {
+		name string
+		file string
+		data []byte
+		opts []Option
+	}{
+		{name: "gzip", file: "urls.txt.gz", data: gz.Bytes()},
+		{name: "zstd", file: "urls.txt.zst", data: zst.Bytes()},
+		{name: "detected from content", file: "urls.bin", data: gz.Bytes(), opts: []Option{WithFormat(FormatText)}},
+		{name: "uncompressed", file: "urls.txt", data: text},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := readRecords(t, tt.file, tt.data, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := []string{"http://example.com/a", "http://example.com/b"}
+			if got := urlsOf(records); !equalStrings(got, expected) {
+				t.Errorf("expected %v, got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestFileReader_CorruptCompressedInput(t *testing.T) {
+	if _, err := readRecords(t, "urls.txt.gz", []byte{0x1F, 0x8B, 0x00, 0x00}); err == nil {
+		t.Error("expected an error for a truncated gzip header, got nil")
+	}
+}
+
+// equalStrings reports whether a and b hold the same strings in the same order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+package filereader
+
+import (
+	"encoding/json"
+	"fmt"
+	"jfrog-assignment/internal/models"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParsePointer splits a JSON pointer (RFC 6901) such as "/artifact/url" into its reference tokens.
+//
+// Parameters:
+//   - pointer: The pointer; "" refers to the whole document.
+//
+// Returns:
+//   - The unescaped reference tokens, or an error if pointer is neither empty nor starts with "/".
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with \"/\"", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// resolvePointer returns the value the reference tokens refer to within a decoded JSON or YAML value.
+func resolvePointer(value any, tokens []string) (any, bool) {
+	for _, token := range tokens {
+		switch v := value.(type) {
+		case map[string]any:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			value = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// parseItem builds a URL record from a JSON, NDJSON or YAML item. An item is either a URL string, or an object
+// holding the URL at the URL pointer and download options under the configured column names.
+//
+// Returns:
+//   - The record, or an error if the item has no URL or an option value is invalid.
+func (fr *FileReader) parseItem(item any) (models.URLRecord, error) {
+	if url, ok := item.(string); ok {
+		return models.URLRecord{URL: strings.TrimSpace(url)}, nil
+	}
+	object, ok := item.(map[string]any)
+	if !ok {
+		return models.URLRecord{}, fmt.Errorf("expected a URL string or an object, got %s", describe(item))
+	}
+
+	tokens, err := ParsePointer(fr.urlPointer)
+	if err != nil {
+		return models.URLRecord{}, err
+	}
+	value, ok := resolvePointer(object, tokens)
+	if !ok {
+		return models.URLRecord{}, fmt.Errorf("no URL at %q", fr.urlPointer)
+	}
+	url, ok := value.(string)
+	if !ok {
+		return models.URLRecord{}, fmt.Errorf("URL at %q is %s, not a string", fr.urlPointer, describe(value))
+	}
+
+	rec := models.URLRecord{URL: strings.TrimSpace(url)}
+	field := func(name string) (any, bool) {
+		if name == "" {
+			return nil, false
+		}
+		for key, value := range object {
+			if strings.EqualFold(key, name) {
+				return value, value != nil
+			}
+		}
+		return nil, false
+	}
+	text := func(name string) (string, error) {
+		value, ok := field(name)
+		if !ok {
+			return "", nil
+		}
+		s, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("%s is %s, not a string", name, describe(value))
+		}
+		return strings.TrimSpace(s), nil
+	}
+
+	if rec.Filename, err = text(fr.columns.Filename); err != nil {
+		return rec, err
+	}
+	if rec.Checksum, err = text(fr.columns.Checksum); err != nil {
+		return rec, err
+	}
+	if rec.Subdir, err = text(fr.columns.Subdir); err != nil {
+		return rec, err
+	}
+	if value, ok := field(fr.columns.Priority); ok {
+		if rec.Priority, err = parsePriority(value); err != nil {
+			return rec, err
+		}
+	}
+	if value, ok := field(fr.columns.Headers); ok {
+		if rec.Headers, err = parseItemHeaders(value); err != nil {
+			return rec, err
+		}
+	}
+	return rec, nil
+}
+
+// parsePriority converts a priority given as a whole number or a numeric string.
+func parsePriority(value any) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= math.MaxInt32 {
+			return int(v), nil
+		}
+	case json.Number:
+		if priority, err := strconv.Atoi(v.String()); err == nil {
+			return priority, nil
+		}
+	case string:
+		if priority, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return priority, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid priority %v", value)
+}
+
+// parseItemHeaders converts headers given as a "Name: value; Other: value" string or as an object of scalar values.
+func parseItemHeaders(value any) (map[string]string, error) {
+	switch v := value.(type) {
+	case string:
+		return parseHeaders(v)
+	case map[string]any:
+		headers := make(map[string]string, len(v))
+		for name, val := range v {
+			switch val.(type) {
+			case map[string]any, []any, nil:
+				return nil, fmt.Errorf("header %s is %s, not a scalar", name, describe(val))
+			}
+			headers[name] = fmt.Sprint(val)
+		}
+		return headers, nil
+	default:
+		return nil, fmt.Errorf("headers are %s, expected a string or an object", describe(value))
+	}
+}
+
+// describe names the JSON type of a decoded value for error messages.
+func describe(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	default:
+		return "a number"
+	}
+}
@@ -0,0 +1,98 @@
+package filereader
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		pointer   string
+		expected  []string
+		expectErr bool
+	}{
+		{pointer: "", expected: nil},
+		{pointer: "/url", expected: []string{"url"}},
+		{pointer: "/artifact/0/href", expected: []string{"artifact", "0", "href"}},
+		{pointer: "/a~1b/c~0d", expected: []string{"a/b", "c~d"}},
+		{pointer: "/", expected: []string{""}},
+		{pointer: "url", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePointer(tt.pointer)
+		if (err != nil) != tt.expectErr || !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ParsePointer(%q) = %q, %v; expected %q, error %v", tt.pointer, got, err, tt.expected, tt.expectErr)
+		}
+	}
+}
+
+func TestFileReader_ParseItem(t *testing.T) {
+	tests := []struct {
+		name      string
+		item      string
+		opts      []Option
+		expected  string
+		priority  int
+		headers   map[string]string
+		expectErr bool
+	}{
+		{name: "string", item: `" http://example.com/a "`, expected: "http://example.com/a"},
+		{name: "object", item: `{"url": "http://example.com/a", "Priority": 3}`, expected: "http://example.com/a", priority: 3},
+		{name: "string priority", item: `{"url": "http://example.com/a", "priority": "7"}`, expected: "http://example.com/a", priority: 7},
+		{
+			name:     "header object",
+			item:     `{"url": "http://example.com/a", "headers": {"X-Trace": 1, "Accept": "*/*"}}`,
+			expected: "http://example.com/a",
+			headers:  map[string]string{"X-Trace": "1", "Accept": "*/*"},
+		},
+		{
+			name:     "header string",
+			item:     `{"url": "http://example.com/a", "headers": "Accept: */*"}`,
+			expected: "http://example.com/a",
+			headers:  map[string]string{"Accept": "*/*"},
+		},
+		{
+			name:     "nested pointer",
+			item:     `{"artifact": {"links": ["http://example.com/a"]}}`,
+			opts:     []Option{WithURLPointer("/artifact/links/0")},
+			expected: "http://example.com/a",
+		},
+		{name: "missing URL", item: `{"href": "http://example.com/a"}`, expectErr: true},
+		{name: "URL not a string", item: `{"url": 42}`, expectErr: true},
+		{name: "fractional priority", item: `{"url": "http://example.com/a", "priority": 1.5}`, expectErr: true},
+		{name: "nested header", item: `{"url": "http://example.com/a", "headers": {"X": [1]}}`, expectErr: true},
+		{name: "array item", item: `["http://example.com/a"]`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := decodeJSON([]byte(tt.item))
+			if err != nil {
+				t.Fatalf("invalid test item: %v", err)
+			}
+			rec, err := New("items.json", tt.opts...).parseItem(item)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got record %+v", rec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rec.URL != tt.expected || rec.Priority != tt.priority || !reflect.DeepEqual(rec.Headers, tt.headers) {
+				t.Errorf("unexpected record %+v", rec)
+			}
+		})
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	for _, value := range []any{json.Number("4"), 4.0, 4, " 4 "} {
+		if got, err := parsePriority(value); err != nil || got != 4 {
+			t.Errorf("parsePriority(%#v) = %d, %v; expected 4", value, got, err)
+		}
+	}
+}
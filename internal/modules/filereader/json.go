@@ -0,0 +1,152 @@
+package filereader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// readNDJSON reads one item per line, skipping blank lines. Lines that are not valid JSON are skipped as malformed.
+func (fr *FileReader) readNDJSON(r io.Reader, sink *recordSink) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		item, err := decodeJSON(data)
+		if err != nil {
+			sink.skip(line, err)
+			continue
+		}
+		if err := fr.emitItem(sink, line, item); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// readJSON reads the items of a JSON document: the array at the items pointer, or the root array if no
+// pointer is set. The document is decoded one token at a time so that the line of every item is known.
+// A value other than an array is read as a single item.
+func (fr *FileReader) readJSON(r io.Reader, sink *recordSink) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	lines := lineCounter{data: data, line: 1}
+	if err := json.Unmarshal(data, new(json.RawMessage)); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return fmt.Errorf("parse %s: line %d: %w", sink.source, lines.next(max(syntaxErr.Offset-1, 0)), err)
+		}
+		return fmt.Errorf("parse %s: %w", sink.source, err)
+	}
+	tokens, err := ParsePointer(fr.itemsPointer)
+	if err != nil {
+		return err
+	}
+
+	// The document is valid, so decoding errors below cannot occur.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if !seekPointer(dec, tokens) {
+		return fmt.Errorf("%s: no items at %q", sink.source, fr.itemsPointer)
+	}
+
+	line := lines.next(dec.InputOffset())
+	if data[lines.offset] != '[' {
+		var item any
+		dec.Decode(&item)
+		return fr.emitItem(sink, line, item)
+	}
+	dec.Token()
+	for dec.More() {
+		line := lines.next(dec.InputOffset())
+		var item any
+		dec.Decode(&item)
+		if err := fr.emitItem(sink, line, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seekPointer advances dec, which must read a valid JSON document, to the start of the value the
+// reference tokens refer to.
+//
+// Returns:
+//   - Whether the value exists.
+func seekPointer(dec *json.Decoder, tokens []string) bool {
+	for _, token := range tokens {
+		delim, _ := dec.Token()
+		index, err := strconv.Atoi(token)
+		if (delim != json.Delim('{') && delim != json.Delim('[')) || (delim == json.Delim('[') && err != nil) {
+			// A scalar has no members, and an array only numeric ones.
+			return false
+		}
+		found := false
+		for i := 0; !found && dec.More(); i++ {
+			if delim == json.Delim('{') {
+				key, _ := dec.Token()
+				found = key == token
+			} else {
+				found = i == index
+			}
+			if !found {
+				dec.Decode(new(json.RawMessage))
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// emitItem converts an item to a record and sends it, or skips it as malformed.
+func (fr *FileReader) emitItem(sink *recordSink, line int, item any) error {
+	rec, err := fr.parseItem(item)
+	if err != nil {
+		sink.skip(line, err)
+		return nil
+	}
+	return sink.emit(line, rec)
+}
+
+// decodeJSON decodes a single JSON value, keeping numbers as json.Number.
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value at offset %d", dec.InputOffset())
+	}
+	return value, nil
+}
+
+// lineCounter maps increasing byte offsets of a document to line numbers.
+type lineCounter struct {
+	data   []byte // The document
+	offset int    // Offset counted up to
+	line   int    // Line of offset
+}
+
+// next returns the line of the first value at or after offset, skipping whitespace and separators.
+func (c *lineCounter) next(offset int64) int {
+	end := int(offset)
+	for end < len(c.data) && bytes.IndexByte([]byte(" \t\r\n,:"), c.data[end]) >= 0 {
+		end++
+	}
+	c.line += bytes.Count(c.data[c.offset:end], []byte{'\n'})
+	c.offset = end
+	return c.line
+}
@@ -0,0 +1,104 @@
+package filereader
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFileReader_JSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		opts      []Option
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "array of strings and objects",
+			data:     `["http://example.com/a", {"url": "http://example.com/b", "filename": "b.bin"}]`,
+			expected: []string{"http://example.com/a", "http://example.com/b"},
+		},
+		{
+			name:     "malformed items are skipped",
+			data:     "[\n  {\"url\": \"http://example.com/a\"},\n  {\"href\": \"x\"},\n  42,\n  \"http://example.com/b\"\n]",
+			expected: []string{"http://example.com/a", "http://example.com/b"},
+		},
+		{
+			name:     "items pointer",
+			data:     `{"version": 2, "artifacts": [{"link": {"href": "http://example.com/a"}}]}`,
+			opts:     []Option{WithItemsPointer("/artifacts"), WithURLPointer("/link/href")},
+			expected: []string{"http://example.com/a"},
+		},
+		{
+			name:     "single object",
+			data:     `{"url": "http://example.com/a"}`,
+			expected: []string{"http://example.com/a"},
+		},
+		{name: "empty array", data: `[]`, expected: nil},
+		{name: "missing items", data: `{"artifacts": []}`, opts: []Option{WithItemsPointer("/files")}, expectErr: true},
+		{
+			name:      "pointer through a scalar",
+			data:      `{"a": 1, "b": ["http://example.com/b"]}`,
+			opts:      []Option{WithItemsPointer("/a/x")},
+			expectErr: true,
+		},
+		{name: "syntax error", data: `[{"url": "http://example.com/a"},`, expectErr: true},
+		{name: "trailing data", data: `{"url": "http://example.com/a"} {}`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := readRecords(t, "urls.json", []byte(tt.data), tt.opts...)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if got := urlsOf(records); !tt.expectErr && !equalStrings(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFileReader_JSONLineNumbers(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	data := "[\n  \"http://example.com/a\",\n\n  {\"href\": \"x\"},\n  {\n    \"url\": 1\n  }\n]"
+	fr := New(writeInput(t, "urls.json", data))
+	if _, err := collect(t, fr, zap.New(core)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var lines []int64
+	for _, entry := range logs.FilterMessage("skipping malformed row").All() {
+		lines = append(lines, entry.ContextMap()["line"].(int64))
+	}
+	if len(lines) != 2 || lines[0] != 4 || lines[1] != 5 {
+		t.Errorf("expected malformed items on lines 4 and 5, got %v", lines)
+	}
+}
+
+func TestFileReader_NDJSON(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	data := "{\"url\": \"http://example.com/a\", \"priority\": 2}\n\n{not json}\n\"http://example.com/b\"\n{\"url\": null}\n"
+	records, err := collect(t, New(writeInput(t, "urls.jsonl", data)), zap.New(core))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"http://example.com/a", "http://example.com/b"}
+	if got := urlsOf(records); !equalStrings(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if records[0].Priority != 2 {
+		t.Errorf("expected priority 2, got %d", records[0].Priority)
+	}
+
+	var lines []int64
+	for _, entry := range logs.FilterMessage("skipping malformed row").All() {
+		lines = append(lines, entry.ContextMap()["line"].(int64))
+	}
+	if len(lines) != 2 || lines[0] != 3 || lines[1] != 5 {
+		t.Errorf("expected malformed lines 3 and 5, got %v", lines)
+	}
+}
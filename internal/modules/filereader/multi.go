@@ -0,0 +1,90 @@
+package filereader
+
+import (
+	"context"
+	"fmt"
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/pipeline"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// MultiReader is a pipeline.Stage reading URLs from several input files, one after another.
+type MultiReader struct {
+	readers []*FileReader // Readers of the input files, in order
+}
+
+var _ pipeline.Stage[struct{}, models.URLRecord] = (*MultiReader)(nil)
+
+// NewMulti creates a MultiReader reading the given files with the same options.
+//
+// Parameters:
+//   - paths: The input files, in the order they are read; see ExpandPaths.
+//   - opts: Options applied to every file.
+//
+// Returns:
+//   - A pointer to a new MultiReader instance.
+func NewMulti(paths []string, opts ...Option) *MultiReader {
+	readers := make([]*FileReader, len(paths))
+	for i, path := range paths {
+		readers[i] = New(path, opts...)
+	}
+	return &MultiReader{readers: readers}
+}
+
+// Execute reads every input file in turn and sends their URL records to the output channel.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - input: Input channel (unused, MultiReader generates its own data).
+//   - output: Channel to send URL records to.
+//   - logger: Logger for logging progress and errors.
+//
+// Returns:
+//   - The error of the first file that cannot be read, nil otherwise.
+func (mr *MultiReader) Execute(ctx context.Context, input <-chan struct{}, output chan<- models.URLRecord, logger *zap.Logger) error {
+	for _, reader := range mr.readers {
+		if err := reader.Execute(ctx, input, output, logger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpandPaths expands glob patterns in a list of input paths. Paths without glob metacharacters are kept
+// as given, so missing files are reported when read. Paths named more than once are only kept the first time.
+//
+// Parameters:
+//   - patterns: Paths, glob patterns as understood by filepath.Glob, or Stdin.
+//
+// Returns:
+//   - The paths in order, or an error if a pattern is malformed or matches no file, or Stdin is given twice.
+func ExpandPaths(patterns []string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		if pattern == Stdin && seen[Stdin] {
+			return nil, fmt.Errorf("standard input (%q) can only be read once", Stdin)
+		}
+
+		matches := []string{pattern}
+		if pattern != Stdin && strings.ContainsAny(pattern, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(pattern); err != nil {
+				return nil, fmt.Errorf("invalid input pattern %q: %w", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no input files match %q", pattern)
+			}
+		}
+		for _, path := range matches {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,81 @@
+package filereader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandPaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.txt", "a.txt", "c.csv"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, b, c := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt"), filepath.Join(dir, "c.csv")
+
+	tests := []struct {
+		name      string
+		patterns  []string
+		expected  []string
+		expectErr bool
+	}{
+		{name: "plain paths are kept", patterns: []string{c, "missing.csv"}, expected: []string{c, "missing.csv"}},
+		{name: "globs are sorted", patterns: []string{filepath.Join(dir, "*.txt")}, expected: []string{a, b}},
+		{name: "duplicates are dropped", patterns: []string{b, filepath.Join(dir, "*")}, expected: []string{b, a, c}},
+		{name: "stdin", patterns: []string{Stdin, c}, expected: []string{Stdin, c}},
+		{name: "stdin twice", patterns: []string{Stdin, Stdin}, expectErr: true},
+		{name: "no match", patterns: []string{filepath.Join(dir, "*.json")}, expectErr: true},
+		{name: "malformed pattern", patterns: []string{filepath.Join(dir, "[")}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandPaths(tt.patterns)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if !tt.expectErr && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMultiReader_Sources(t *testing.T) {
+	csvPath := writeInput(t, "urls.csv", "url\nhttp://example.com/a\n\"http://example.com/\nb\"\n")
+	jsonPath := writeInput(t, "urls.json", "{\n  \"items\": [\n    \"http://example.com/c\",\n    {\"url\": \"http://example.com/d\"}\n  ]\n}")
+	yamlPath := writeInput(t, "urls.yaml", "items:\n  - http://example.com/e\n")
+	stdin := strings.NewReader("url\n\nhttp://example.com/f\n")
+
+	mr := NewMulti([]string{csvPath, Stdin, jsonPath, yamlPath}, WithStdin(stdin), WithItemsPointer("/items"))
+	records, err := collectMulti(t, mr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		csvPath + ":2", csvPath + ":3", "stdin:3", jsonPath + ":3", jsonPath + ":4", yamlPath + ":2",
+	}
+	var origins []string
+	for _, rec := range records {
+		origins = append(origins, rec.Origin())
+	}
+	if !reflect.DeepEqual(origins, expected) {
+		t.Errorf("expected origins %v, got %v", expected, origins)
+	}
+}
+
+func TestMultiReader_StopsAtFirstError(t *testing.T) {
+	path := writeInput(t, "urls.txt", "http://example.com/a\n")
+	records, err := collectMulti(t, NewMulti([]string{path, filepath.Join(t.TempDir(), "missing.txt"), path}))
+	if err == nil {
+		t.Fatal("expected an error for the missing file, got nil")
+	}
+	if len(records) != 1 {
+		t.Errorf("expected only the records of the first file, got %+v", records)
+	}
+}
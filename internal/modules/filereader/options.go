@@ -0,0 +1,126 @@
+package filereader
+
+import "io"
+
+// Option configures a FileReader.
+type Option func(*FileReader)
+
+// WithURLColumn selects the column holding the URL, either by header name (case-insensitive)
+// or by zero-based index. The first column is used by default.
+//
+// Parameters:
+//   - column: The column name or index.
+//
+// Returns:
+//   - An Option selecting the URL column.
+func WithURLColumn(column string) Option {
+	return func(fr *FileReader) {
+		fr.urlColumn = column
+	}
+}
+
+// WithDelimiter sets the field delimiter. The default is a comma.
+//
+// Parameters:
+//   - delimiter: The field delimiter.
+//
+// Returns:
+//   - An Option setting the delimiter.
+func WithDelimiter(delimiter rune) Option {
+	return func(fr *FileReader) {
+		fr.delimiter = delimiter
+	}
+}
+
+// WithComment makes lines starting with the given character be ignored. Comments are disabled by default.
+//
+// Parameters:
+//   - comment: The comment character.
+//
+// Returns:
+//   - An Option enabling comment lines.
+func WithComment(comment rune) Option {
+	return func(fr *FileReader) {
+		fr.comment = comment
+	}
+}
+
+// WithHeader sets whether the first record is a header row. A header is expected by default.
+//
+// Parameters:
+//   - header: Whether the file starts with a header row.
+//
+// Returns:
+//   - An Option setting header handling.
+func WithHeader(header bool) Option {
+	return func(fr *FileReader) {
+		fr.header = header
+	}
+}
+
+// WithColumns sets the columns holding per-row download options. DefaultColumns is used by default.
+//
+// Parameters:
+//   - columns: The option columns; empty names disable the corresponding option.
+//
+// Returns:
+//   - An Option setting the option columns.
+func WithColumns(columns Columns) Option {
+	return func(fr *FileReader) {
+		fr.columns = columns
+	}
+}
+
+// WithFormat sets the input format. By default it is detected from the file extension; see DetectFormat.
+//
+// Parameters:
+//   - format: The input format.
+//
+// Returns:
+//   - An Option setting the format.
+func WithFormat(format Format) Option {
+	return func(fr *FileReader) {
+		fr.format = format
+	}
+}
+
+// WithURLPointer sets the JSON pointer (RFC 6901) to the URL within a JSON, NDJSON or YAML item.
+// DefaultURLPointer is used by default. Items that are plain strings are always read as URLs.
+//
+// Parameters:
+//   - pointer: The pointer, e.g. "/artifact/url".
+//
+// Returns:
+//   - An Option setting the URL pointer.
+func WithURLPointer(pointer string) Option {
+	return func(fr *FileReader) {
+		fr.urlPointer = pointer
+	}
+}
+
+// WithItemsPointer sets the JSON pointer (RFC 6901) to the list of items within a JSON document or each
+// YAML document. By default the document root holds the items.
+//
+// Parameters:
+//   - pointer: The pointer, e.g. "/artifacts".
+//
+// Returns:
+//   - An Option setting the items pointer.
+func WithItemsPointer(pointer string) Option {
+	return func(fr *FileReader) {
+		fr.itemsPointer = pointer
+	}
+}
+
+// WithStdin sets the reader the path Stdin reads from. os.Stdin is used by default.
+//
+// Parameters:
+//   - r: The standard input.
+//
+// Returns:
+//   - An Option setting standard input.
+func WithStdin(r io.Reader) Option {
+	return func(fr *FileReader) {
+		fr.stdin = r
+	}
+}
@@ -0,0 +1,27 @@
+package filereader
+
+import (
+	"bufio"
+	"io"
+	"jfrog-assignment/internal/models"
+	"strings"
+)
+
+// maxLineSize is the longest line accepted in text and NDJSON input.
+const maxLineSize = 1 << 20
+
+// readText reads one URL per line, ignoring blank lines and lines starting with #.
+func readText(r io.Reader, sink *recordSink) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if err := sink.emit(line, models.URLRecord{URL: text}); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
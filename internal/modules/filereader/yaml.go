@@ -0,0 +1,91 @@
+package filereader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readYAML reads the items of every document in a YAML stream: the sequence at the items pointer, or the
+// document root if no pointer is set. A node other than a sequence is read as a single item.
+func (fr *FileReader) readYAML(r io.Reader, sink *recordSink) error {
+	tokens, err := ParsePointer(fr.itemsPointer)
+	if err != nil {
+		return err
+	}
+
+	dec := yaml.NewDecoder(r)
+	for {
+		var document yaml.Node
+		err := dec.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", sink.source, err)
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+
+		items, ok := resolveNode(document.Content[0], tokens)
+		if !ok {
+			return fmt.Errorf("%s: no items at %q in document on line %d", sink.source, fr.itemsPointer, document.Line)
+		}
+		list := []*yaml.Node{items}
+		if items.Kind == yaml.SequenceNode {
+			list = items.Content
+		}
+		for _, node := range list {
+			var item any
+			if err := node.Decode(&item); err != nil {
+				sink.skip(node.Line, err)
+				continue
+			}
+			if err := fr.emitItem(sink, node.Line, item); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// resolveNode returns the node the reference tokens refer to, following aliases.
+func resolveNode(node *yaml.Node, tokens []string) (*yaml.Node, bool) {
+	for _, token := range tokens {
+		node = dealias(node)
+		switch node.Kind {
+		case yaml.MappingNode:
+			var found *yaml.Node
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					found = node.Content[i+1]
+					break
+				}
+			}
+			if found == nil {
+				return nil, false
+			}
+			node = found
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return nil, false
+			}
+			node = node.Content[index]
+		default:
+			return nil, false
+		}
+	}
+	return dealias(node), true
+}
+
+// dealias returns the node an alias refers to, or node itself.
+func dealias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
@@ -0,0 +1,76 @@
+package filereader
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFileReader_YAML(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		opts      []Option
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "sequence of strings and mappings",
+			data:     "- http://example.com/a\n- url: http://example.com/b\n  priority: 3\n",
+			expected: []string{"http://example.com/a", "http://example.com/b"},
+		},
+		{
+			name:     "items pointer across documents",
+			data:     "artifacts:\n  - url: http://example.com/a\n---\nartifacts:\n  - url: http://example.com/b\n",
+			opts:     []Option{WithItemsPointer("/artifacts")},
+			expected: []string{"http://example.com/a", "http://example.com/b"},
+		},
+		{
+			name:     "aliases",
+			data:     "base: &base\n  - http://example.com/a\nitems: *base\n",
+			opts:     []Option{WithItemsPointer("/items")},
+			expected: []string{"http://example.com/a"},
+		},
+		{
+			name:     "malformed items are skipped",
+			data:     "- url: http://example.com/a\n- href: x\n- [1, 2]\n",
+			expected: []string{"http://example.com/a"},
+		},
+		{name: "empty document", data: "", expected: nil},
+		{name: "missing items", data: "artifacts: []\n", opts: []Option{WithItemsPointer("/files")}, expectErr: true},
+		{name: "syntax error", data: "- url: [http://example.com/a\n", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := readRecords(t, "urls.yaml", []byte(tt.data), tt.opts...)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if got := urlsOf(records); !tt.expectErr && !equalStrings(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFileReader_YAMLOptions(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	data := "- url: http://example.com/a\n  filename: a.bin\n  headers:\n    Accept: '*/*'\n  priority: 9\n- url: http://example.com/b\n  priority: high\n"
+	records, err := collect(t, New(writeInput(t, "urls.yml", data)), zap.New(core))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %+v", records)
+	}
+	if a := records[0]; a.Filename != "a.bin" || a.Priority != 9 || a.Headers["Accept"] != "*/*" {
+		t.Errorf("unexpected record %+v", a)
+	}
+
+	skipped := logs.FilterMessage("skipping malformed row").All()
+	if len(skipped) != 1 || skipped[0].ContextMap()["line"] != int64(6) {
+		t.Errorf("expected the item on line 6 to be skipped, got %v", skipped)
+	}
+}
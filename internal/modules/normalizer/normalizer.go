@@ -0,0 +1,95 @@
+package normalizer
+
+import (
+	"context"
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/pipeline"
+
+	"go.uber.org/zap"
+)
+
+// Normalizer is a pipeline.Stage that validates and normalizes the URL of every record and drops duplicates.
+// Records whose URL is invalid are logged with the line they were read from and skipped. A record is a
+// duplicate of an earlier one if both have the same normalized URL and would be saved to the same place.
+type Normalizer struct {
+	preferHTTPS bool // Whether URLs without a scheme, and http URLs on the default port, use https
+}
+
+// recordKey identifies records that would download the same URL to the same file.
+type recordKey struct {
+	url, filename, subdir string
+}
+
+var _ pipeline.Stage[models.URLRecord, models.URLRecord] = (*Normalizer)(nil)
+
+// New creates a new Normalizer instance.
+//
+// Parameters:
+//   - opts: Optional settings; by default URLs without a scheme use http.
+//
+// Returns:
+//   - A pointer to a new Normalizer instance.
+func New(opts ...Option) *Normalizer {
+	n := &Normalizer{}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Execute normalizes the records received on the input channel and sends the valid, unique ones to the
+// output channel, in order.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - input: Channel to receive URL records from.
+//   - output: Channel to send normalized URL records to.
+//   - logger: Logger for logging rejected records and statistics.
+//
+// Returns:
+//   - ctx.Err() if ctx is canceled, nil otherwise.
+func (n *Normalizer) Execute(ctx context.Context, input <-chan models.URLRecord, output chan<- models.URLRecord, logger *zap.Logger) error {
+	var accepted, invalid, duplicates int
+	seen := make(map[recordKey]string)
+
+	for rec := range input {
+		normalized, err := Normalize(rec.URL, n.preferHTTPS)
+		if err != nil {
+			logger.Warn("skipping invalid URL",
+				zap.String("url", rec.URL),
+				rec.OriginField(),
+				zap.Error(err))
+			invalid++
+			continue
+		}
+		if normalized != rec.URL {
+			logger.Debug("normalized URL", zap.String("url", rec.URL), zap.String("normalized", normalized))
+			rec.URL = normalized
+		}
+
+		key := recordKey{url: rec.URL, filename: rec.Filename, subdir: rec.Subdir}
+		if first, ok := seen[key]; ok {
+			logger.Debug("skipping duplicate URL",
+				zap.String("url", rec.URL),
+				rec.OriginField(),
+				zap.String("first_seen", first))
+			duplicates++
+			continue
+		}
+		seen[key] = rec.Origin()
+
+		select {
+		case <-ctx.Done():
+			logger.Warn("URL normalization interrupted", zap.Error(ctx.Err()))
+			return ctx.Err()
+		case output <- rec:
+			accepted++
+		}
+	}
+
+	logger.Info("URL normalization statistics",
+		zap.Int("accepted", accepted),
+		zap.Int("invalid", invalid),
+		zap.Int("duplicates", duplicates))
+	return nil
+}
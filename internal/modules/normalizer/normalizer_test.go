@@ -0,0 +1,103 @@
+package normalizer
+
+import (
+	"context"
+	"jfrog-assignment/internal/models"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// normalize runs a Normalizer over records and returns the records it sent and its log.
+func normalize(t *testing.T, n *Normalizer, records ...models.URLRecord) ([]models.URLRecord, *observer.ObservedLogs) {
+	t.Helper()
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	input := make(chan models.URLRecord, len(records))
+	for _, rec := range records {
+		input <- rec
+	}
+	close(input)
+	output := make(chan models.URLRecord, len(records))
+
+	if err := n.Execute(context.Background(), input, output, zap.New(core)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(output)
+
+	var got []models.URLRecord
+	for rec := range output {
+		got = append(got, rec)
+	}
+	return got, logs
+}
+
+func TestNormalizer_Execute(t *testing.T) {
+	got, logs := normalize(t, New(),
+		models.URLRecord{URL: "Example.com/a", Source: "urls.csv", Line: 2},
+		models.URLRecord{URL: "ftp://example.com/b", Source: "urls.csv", Line: 3},
+		models.URLRecord{URL: "http://example.com:80/a#top", Source: "urls.csv", Line: 4},
+		models.URLRecord{URL: "http://example.com/a", Filename: "copy.bin", Source: "urls.csv", Line: 5},
+		models.URLRecord{URL: "http://example.com/c", Priority: 3, Source: "more.txt", Line: 1},
+		models.URLRecord{URL: "http://example.com/a", Source: "more.txt", Line: 2},
+	)
+
+	expected := []models.URLRecord{
+		{URL: "http://example.com/a", Source: "urls.csv", Line: 2},
+		{URL: "http://example.com/a", Filename: "copy.bin", Source: "urls.csv", Line: 5},
+		{URL: "http://example.com/c", Priority: 3, Source: "more.txt", Line: 1},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d records, got %+v", len(expected), got)
+	}
+	for i := range expected {
+		if got[i].URL != expected[i].URL || got[i].Filename != expected[i].Filename ||
+			got[i].Priority != expected[i].Priority || got[i].Origin() != expected[i].Origin() {
+			t.Errorf("record %d: expected %+v, got %+v", i, expected[i], got[i])
+		}
+	}
+
+	invalid := logs.FilterMessage("skipping invalid URL").All()
+	if len(invalid) != 1 || invalid[0].ContextMap()["source"] != "urls.csv:3" {
+		t.Errorf("expected the URL on urls.csv:3 to be rejected, got %v", invalid)
+	}
+	duplicates := logs.FilterMessage("skipping duplicate URL").All()
+	if len(duplicates) != 2 || duplicates[1].ContextMap()["first_seen"] != "urls.csv:2" {
+		t.Errorf("expected two duplicates of urls.csv:2, got %v", duplicates)
+	}
+
+	stats := logs.FilterMessage("URL normalization statistics").All()
+	if len(stats) != 1 {
+		t.Fatalf("expected one statistics entry, got %d", len(stats))
+	}
+	fields := stats[0].ContextMap()
+	if fields["accepted"] != int64(3) || fields["invalid"] != int64(1) || fields["duplicates"] != int64(2) {
+		t.Errorf("unexpected statistics %v", fields)
+	}
+}
+
+func TestNormalizer_PreferHTTPS(t *testing.T) {
+	got, _ := normalize(t, New(WithPreferHTTPS()),
+		models.URLRecord{URL: "example.com/a"},
+		models.URLRecord{URL: "https://example.com/a"},
+		models.URLRecord{URL: "http://example.com:8080/b"},
+	)
+	if len(got) != 2 || got[0].URL != "https://example.com/a" || got[1].URL != "http://example.com:8080/b" {
+		t.Errorf("unexpected records %+v", got)
+	}
+}
+
+func TestNormalizer_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	input := make(chan models.URLRecord, 1)
+	input <- models.URLRecord{URL: "http://example.com/a"}
+	close(input)
+
+	if err := New().Execute(ctx, input, make(chan models.URLRecord), zap.NewNop()); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
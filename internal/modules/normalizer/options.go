@@ -0,0 +1,15 @@
+package normalizer
+
+// Option configures a Normalizer.
+type Option func(*Normalizer)
+
+// WithPreferHTTPS makes URLs without a scheme use https, and upgrades http URLs on the default port to https.
+// URLs without a scheme use http by default.
+//
+// Returns:
+//   - An Option preferring https.
+func WithPreferHTTPS() Option {
+	return func(n *Normalizer) {
+		n.preferHTTPS = true
+	}
+}
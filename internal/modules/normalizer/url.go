@@ -0,0 +1,103 @@
+package normalizer
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// defaultPorts maps each supported scheme to its default port.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// hostProfile converts internationalized host names to their ASCII form for lookup. Unlike idna.Lookup it
+// accepts underscores, which occur in real host names.
+var hostProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.Transitional(false),
+	idna.StrictDomainName(false),
+)
+
+// Normalize validates an HTTP or HTTPS URL and returns its normalized form: the scheme and host are
+// lowercased, internationalized host names are converted to punycode, the default port and the fragment
+// are removed, and a missing scheme is filled in.
+//
+// Parameters:
+//   - raw: The URL, with or without a scheme.
+//   - preferHTTPS: Whether URLs without a scheme, and http URLs on the default port, use https.
+//
+// Returns:
+//   - The normalized URL, or an error describing why raw is not a valid HTTP or HTTPS URL.
+func Normalize(raw string, preferHTTPS bool) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("empty URL")
+	}
+	if !strings.Contains(raw, "://") {
+		scheme := "http"
+		if preferHTTPS {
+			scheme = "https"
+		}
+		raw = scheme + "://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := defaultPorts[u.Scheme]; !ok {
+		return "", fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if u.Opaque != "" || u.Host == "" {
+		return "", fmt.Errorf("missing host in %q", raw)
+	}
+
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return "", err
+	}
+	port := u.Port()
+	if port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", fmt.Errorf("invalid port %q", port)
+		}
+	}
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+	if preferHTTPS && u.Scheme == "http" && port == "" {
+		u.Scheme = "https"
+	}
+
+	u.Host = host
+	if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	}
+	if port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	}
+	u.Fragment, u.RawFragment = "", ""
+	return u.String(), nil
+}
+
+// normalizeHost lowercases a host name or IP address and converts internationalized names to punycode.
+func normalizeHost(host string) (string, error) {
+	if host == "" {
+		return "", fmt.Errorf("missing host")
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+	ascii, err := hostProfile.ToASCII(host)
+	if err != nil {
+		return "", fmt.Errorf("invalid host %q: %w", host, err)
+	}
+	return strings.ToLower(ascii), nil
+}
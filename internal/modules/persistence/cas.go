@@ -0,0 +1,404 @@
+package persistence
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/storage"
+	"maps"
+	"os"
+	"path/filepath"
+)
+
+// LinkMode selects how a content-addressed entry refers to its blob.
+type LinkMode string
+
+const (
+	LinkHardlink LinkMode = "hardlink" // The entry is a hard link to the blob
+	LinkSymlink  LinkMode = "symlink"  // The entry is a relative symbolic link to the blob
+	LinkIndex    LinkMode = "index"    // The entry only exists as a record in the index
+)
+
+const (
+	casBlobDir      = "sha256"         // Directory of the download directory holding blobs
+	casIndexFile    = "cas-index.json" // File in the download directory recording entries and reference counts
+	casBlobFileMode = 0444             // Blobs are read-only, since hard-linked entries share them
+)
+
+// ParseLinkMode parses the name of a LinkMode.
+//
+// Parameters:
+//   - value: "hardlink", "symlink" or "index".
+//
+// Returns:
+//   - The LinkMode, or an error if value is not a known mode.
+func ParseLinkMode(value string) (LinkMode, error) {
+	switch mode := LinkMode(value); mode {
+	case LinkHardlink, LinkSymlink, LinkIndex:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown CAS link mode %q (expected hardlink, symlink or index)", value)
+	}
+}
+
+// casEntry is the index record of a persisted file.
+type casEntry struct {
+	Digest string   `json:"digest"` // Hex SHA-256 of the payload
+	Mode   LinkMode `json:"mode"`   // How the entry refers to its blob
+}
+
+// casIndex is the on-disk form of a CASStore's bookkeeping.
+type casIndex struct {
+	Entries map[string]casEntry `json:"entries"` // Entries keyed by slash-separated path relative to the store root
+	Refs    map[string]int      `json:"refs"`    // Number of entries referencing each blob, keyed by digest
+}
+
+// CASStore stores payloads once under sha256/ab/cd/<digest> and makes every persisted file an entry
+// referring to its blob. Entries are reference counted in cas-index.json so that unreferenced blobs
+// can be pruned with GC. A CASStore is not safe for concurrent use.
+type CASStore struct {
+	root  string   // Download directory holding blobs, entries and the index
+	mode  LinkMode // How new entries refer to their blob
+	index casIndex // Entries and reference counts
+	dirty bool     // Whether entries were forgotten since the index was loaded or saved
+}
+
+// GCStats summarizes a garbage collection.
+type GCStats struct {
+	StaleEntries int   // Entries dropped because their file was removed or replaced
+	Blobs        int   // Unreferenced blobs removed
+	Bytes        int64 // Bytes freed by removing blobs
+}
+
+// OpenCAS opens the content-addressable store in root, loading its index if one exists.
+//
+// Parameters:
+//   - root: The download directory.
+//   - mode: How entries created by this store refer to their blob.
+//
+// Returns:
+//   - The store, or an error if the index exists but cannot be read or parsed, or holds a digest that is not
+//     a hex SHA-256.
+func OpenCAS(root string, mode LinkMode) (*CASStore, error) {
+	s := &CASStore{
+		root:  root,
+		mode:  mode,
+		index: casIndex{Entries: make(map[string]casEntry), Refs: make(map[string]int)},
+	}
+
+	data, err := os.ReadFile(s.indexPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read CAS index: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.index); err != nil {
+		return nil, fmt.Errorf("parse CAS index %s: %w", s.indexPath(), err)
+	}
+	if s.index.Entries == nil {
+		s.index.Entries = make(map[string]casEntry)
+	}
+	if s.index.Refs == nil {
+		s.index.Refs = make(map[string]int)
+	}
+	// Digests become blob paths, so a malformed one in a hand-edited or corrupted index is rejected up front.
+	for key, entry := range s.index.Entries {
+		if !isDigest(entry.Digest) {
+			return nil, fmt.Errorf("parse CAS index %s: entry %q has invalid digest %q", s.indexPath(), key, entry.Digest)
+		}
+	}
+	for digest := range s.index.Refs {
+		if !isDigest(digest) {
+			return nil, fmt.Errorf("parse CAS index %s: invalid digest %q in reference counts", s.indexPath(), digest)
+		}
+	}
+	return s, nil
+}
+
+// isDigest reports whether s is a lowercase hex SHA-256 digest, as blobs are named.
+func isDigest(s string) bool {
+	if len(s) != 2*sha256.Size {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// BlobPath returns the path of the blob with the given hex SHA-256 digest.
+func (s *CASStore) BlobPath(digest string) string {
+	return filepath.Join(s.root, casBlobDir, digest[:2], digest[2:4], digest)
+}
+
+// indexPath returns the path of the store's index file.
+func (s *CASStore) indexPath() string {
+	return filepath.Join(s.root, casIndexFile)
+}
+
+// put stores the payload of c as a blob, unless an identical blob exists, and makes key an entry referring to it.
+// A streamed body is copied incrementally and closed.
+//
+// Parameters:
+//   - key: Slash-separated path of the entry, relative to the store root.
+//   - c: The content to store.
+//
+// Returns:
+//   - The key the payload can be read from: the entry itself, or the blob in index mode.
+//   - The hex SHA-256 of the payload.
+//   - An error if the blob or the entry cannot be written.
+func (s *CASStore) put(key string, c models.Content) (string, string, error) {
+	digest, err := s.writeBlob(c)
+	if err != nil {
+		return "", "", err
+	}
+	blob := s.BlobPath(digest)
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+
+	readKey := key
+	switch s.mode {
+	case LinkHardlink:
+		err = replaceWithLink(path, func(tmp string) error { return os.Link(blob, tmp) })
+	case LinkSymlink:
+		var target string
+		if target, err = filepath.Rel(filepath.Dir(path), blob); err == nil {
+			err = replaceWithLink(path, func(tmp string) error { return os.Symlink(target, tmp) })
+		}
+	case LinkIndex:
+		readKey = filepath.ToSlash(filepath.Join(casBlobDir, digest[:2], digest[2:4], digest))
+		if err = os.Remove(path); errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	if old, ok := s.index.Entries[key]; ok {
+		s.release(old.Digest)
+	}
+	s.index.Entries[key] = casEntry{Digest: digest, Mode: s.mode}
+	s.index.Refs[digest]++
+	return readKey, digest, nil
+}
+
+// replaceWithLink atomically replaces path with a link created by link, which is called with a
+// temporary name in the same directory, so readers never find path missing or half-replaced.
+func replaceWithLink(path string, link func(tmp string) error) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	// Reserve a unique temporary name, then put the link in its place.
+	reserved, err := os.CreateTemp(dir, storage.TempPattern)
+	if err != nil {
+		return err
+	}
+	tmp := reserved.Name()
+	reserved.Close()
+	if err := os.Remove(tmp); err != nil {
+		return err
+	}
+
+	if err := link(tmp); err != nil {
+		return err
+	}
+	err = os.Rename(tmp, path)
+	// Renaming a hard link over another link to the same file succeeds without removing tmp.
+	os.Remove(tmp)
+	return err
+}
+
+// writeBlob hashes the payload of c while writing it to a temporary file, then moves the file into place
+// unless a blob with the same digest already exists.
+//
+// Returns:
+//   - The hex SHA-256 digest of the payload, or an error if writing fails.
+func (s *CASStore) writeBlob(c models.Content) (string, error) {
+	dir := filepath.Join(s.root, casBlobDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		closeBody(c)
+		return "", err
+	}
+	tmp, err := os.CreateTemp(dir, storage.TempPattern)
+	if err != nil {
+		closeBody(c)
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	var src io.Reader = bytes.NewReader(c.Data)
+	if c.Body != nil {
+		defer c.Body.Close()
+		src = c.Body
+	}
+	if _, err := io.Copy(io.MultiWriter(tmp, h), src); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	digest := hex.EncodeToString(h.Sum(nil))
+	blob := s.BlobPath(digest)
+	if _, err := os.Stat(blob); err == nil {
+		return digest, nil // Deduplicated: the temporary file is removed by the deferred call.
+	}
+	if err := os.MkdirAll(filepath.Dir(blob), 0755); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), casBlobFileMode); err != nil {
+		return "", err
+	}
+	return digest, os.Rename(tmp.Name(), blob)
+}
+
+// release drops one reference to the blob with the given digest.
+func (s *CASStore) release(digest string) {
+	if s.index.Refs[digest] <= 1 {
+		delete(s.index.Refs, digest)
+		return
+	}
+	s.index.Refs[digest]--
+}
+
+// Forget drops the entry with the given key from the index and releases its reference, so that GC removes
+// its blob once no other entry refers to it. This is how index entries, which have no file to delete, are
+// removed; the file of a linked entry is left as is.
+//
+// Parameters:
+//   - key: Slash-separated path of the entry, relative to the store root.
+//
+// Returns:
+//   - Whether the store had an entry with that key.
+func (s *CASStore) Forget(key string) bool {
+	entry, ok := s.index.Entries[key]
+	if !ok {
+		return false
+	}
+	delete(s.index.Entries, key)
+	s.release(entry.Digest)
+	s.dirty = true
+	return true
+}
+
+// Save writes the index back to the store root.
+//
+// Returns:
+//   - An error if the index cannot be written, nil otherwise.
+func (s *CASStore) Save() error {
+	data, err := json.MarshalIndent(s.index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := storage.WriteFileAtomic(s.indexPath(), bytes.NewReader(data), 0644); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// GC drops entries whose file was removed or replaced since it was persisted, recomputes the reference
+// counts, and removes blobs that are no longer referenced along with leftover temporary blobs.
+//
+// Parameters:
+//   - dryRun: Only report what would be removed, without changing anything.
+//
+// Returns:
+//   - Statistics of the collection, or an error if the blob directory cannot be walked or the index cannot be saved.
+func (s *CASStore) GC(dryRun bool) (GCStats, error) {
+	var stats GCStats
+
+	refs := make(map[string]int)
+	for key, entry := range s.index.Entries {
+		if !s.isLive(key, entry) {
+			stats.StaleEntries++
+			if !dryRun {
+				delete(s.index.Entries, key)
+			}
+			continue
+		}
+		refs[entry.Digest]++
+	}
+
+	err := filepath.WalkDir(filepath.Join(s.root, casBlobDir), func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name := d.Name()
+		if !storage.IsTemp(name) && refs[name] > 0 {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stats.Blobs++
+		stats.Bytes += info.Size()
+		if dryRun {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		// Prune the now possibly empty fan-out directories; failures just mean they are still in use.
+		os.Remove(filepath.Dir(path))
+		os.Remove(filepath.Dir(filepath.Dir(path)))
+		return nil
+	})
+	// Save whenever the recomputed counts differ, so drift in the stored ones is corrected even with nothing to prune,
+	// and whenever entries were forgotten.
+	if err != nil || dryRun || (stats == (GCStats{}) && maps.Equal(refs, s.index.Refs) && !s.dirty) {
+		return stats, err
+	}
+
+	s.index.Refs = refs
+	return stats, s.Save()
+}
+
+// isLive reports whether an entry still refers to its blob.
+// Index entries do as long as the blob exists; linked entries stop doing so once their file is removed or replaced.
+func (s *CASStore) isLive(key string, entry casEntry) bool {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	blob := s.BlobPath(entry.Digest)
+
+	switch entry.Mode {
+	case LinkHardlink:
+		entryInfo, err := os.Lstat(path)
+		if err != nil {
+			return false
+		}
+		blobInfo, err := os.Stat(blob)
+		return err == nil && os.SameFile(entryInfo, blobInfo)
+	case LinkSymlink:
+		target, err := os.Readlink(path)
+		if err != nil {
+			return false
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		return filepath.Clean(target) == filepath.Clean(blob)
+	default:
+		_, err := os.Stat(blob)
+		return err == nil
+	}
+}
@@ -0,0 +1,262 @@
+package persistence
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"jfrog-assignment/internal/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func digestOf(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func persistCAS(t *testing.T, dir string, mode LinkMode, contents ...models.Content) *CASStore {
+	t.Helper()
+	store, err := OpenCAS(dir, mode)
+	if err != nil {
+		t.Fatalf("opening store failed: %v", err)
+	}
+
+	input := make(chan models.Content, len(contents))
+	for _, c := range contents {
+		input <- c
+	}
+	close(input)
+
+	fp := New(dir, WithCAS(store))
+	if err := fp.Execute(context.Background(), input, make(chan struct{}), zaptest.NewLogger(t)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return store
+}
+
+func named(url, filename, data string) models.Content {
+	return models.Content{URL: url, Record: models.URLRecord{URL: url, Filename: filename}, Data: []byte(data)}
+}
+
+func TestCASStore_Deduplicates(t *testing.T) {
+	for _, mode := range []LinkMode{LinkHardlink, LinkSymlink, LinkIndex} {
+		t.Run(string(mode), func(t *testing.T) {
+			dir := t.TempDir()
+			store := persistCAS(t, dir, mode,
+				named("http://a.example.com/app.jar", "a.jar", "payload"),
+				models.Content{
+					URL:    "http://b.example.com/app.jar",
+					Record: models.URLRecord{URL: "http://b.example.com/app.jar", Filename: "b.jar"},
+					Body:   io.NopCloser(strings.NewReader("payload")),
+				},
+			)
+
+			digest := digestOf("payload")
+			if blob, want := store.BlobPath(digest), filepath.Join(dir, "sha256", digest[:2], digest[2:4], digest); blob != want {
+				t.Errorf("expected blob at %s, got %s", want, blob)
+			}
+			blobs, _ := filepath.Glob(filepath.Join(dir, "sha256", "*", "*", "*"))
+			if len(blobs) != 1 {
+				t.Fatalf("expected 1 blob, got %v", blobs)
+			}
+
+			for _, name := range []string{"a.jar", "b.jar"} {
+				data, err := os.ReadFile(filepath.Join(dir, name))
+				if mode == LinkIndex {
+					if err == nil {
+						t.Errorf("expected no file for index entry %s", name)
+					}
+					continue
+				}
+				if err != nil || string(data) != "payload" {
+					t.Errorf("expected %s to read the blob, got %q, %v", name, data, err)
+				}
+			}
+
+			// Persisting an entry again with the same payload replaces it in place.
+			reopened := persistCAS(t, dir, mode, named("http://a.example.com/app.jar", "a.jar", "payload"))
+			if refs := reopened.index.Refs[digestOf("payload")]; refs != 2 {
+				t.Errorf("expected 2 references, got %d", refs)
+			}
+			if temps, _ := filepath.Glob(filepath.Join(dir, ".urldownloader-*")); len(temps) != 0 {
+				t.Errorf("expected no temporary files, got %v", temps)
+			}
+		})
+	}
+}
+
+func TestCASStore_GC(t *testing.T) {
+	dir := t.TempDir()
+	persistCAS(t, dir, LinkHardlink,
+		named("http://example.com/a", "a.bin", "kept"),
+		named("http://example.com/b", "b.bin", "removed"),
+		named("http://example.com/c", "c.bin", "replaced"),
+	)
+	// Re-persisting an entry with new content drops the reference to its old blob.
+	store := persistCAS(t, dir, LinkSymlink, named("http://example.com/c", "c.bin", "kept"))
+
+	if err := os.Remove(filepath.Join(dir, "b.bin")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.index.Refs[digestOf("replaced")]; ok {
+		t.Errorf("expected replaced blob to be unreferenced")
+	}
+
+	stats, err := store.GC(true)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if stats.StaleEntries != 1 || stats.Blobs != 2 {
+		t.Errorf("expected 1 stale entry and 2 blobs in dry run, got %+v", stats)
+	}
+	if _, err := os.Stat(store.BlobPath(digestOf("removed"))); err != nil {
+		t.Errorf("expected dry run to keep blobs: %v", err)
+	}
+
+	stats, err = store.GC(false)
+	if err != nil {
+		t.Fatalf("gc failed: %v", err)
+	}
+	if stats.StaleEntries != 1 || stats.Blobs != 2 || stats.Bytes != int64(len("removed")+len("replaced")) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	for _, data := range []string{"removed", "replaced"} {
+		if _, err := os.Stat(store.BlobPath(digestOf(data))); !os.IsNotExist(err) {
+			t.Errorf("expected blob of %q to be removed, got %v", data, err)
+		}
+	}
+	for _, name := range []string{"a.bin", "c.bin"} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(data) != "kept" {
+			t.Errorf("expected %s to survive gc, got %q, %v", name, data, err)
+		}
+	}
+
+	reopened, err := OpenCAS(dir, LinkHardlink)
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	if len(reopened.index.Entries) != 2 || reopened.index.Refs[digestOf("kept")] != 2 {
+		t.Errorf("unexpected index after gc: %+v", reopened.index)
+	}
+}
+
+func TestParseLinkMode(t *testing.T) {
+	if mode, err := ParseLinkMode("symlink"); err != nil || mode != LinkSymlink {
+		t.Errorf("expected symlink, got %q, %v", mode, err)
+	}
+	if _, err := ParseLinkMode("copy"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestCASStore_GCCorrectsRefs(t *testing.T) {
+	dir := t.TempDir()
+	store := persistCAS(t, dir, LinkHardlink,
+		named("http://example.com/a", "a.bin", "kept"),
+		named("http://example.com/b", "b.bin", "kept"),
+	)
+
+	// Simulate drift in the stored counts without anything left to prune.
+	store.index.Refs[digestOf("kept")] = 5
+	store.index.Refs[digestOf("gone")] = 1
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := store.GC(false)
+	if err != nil {
+		t.Fatalf("gc failed: %v", err)
+	}
+	if stats != (GCStats{}) {
+		t.Errorf("expected nothing to be pruned, got %+v", stats)
+	}
+
+	reopened, err := OpenCAS(dir, LinkHardlink)
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	if len(reopened.index.Refs) != 1 || reopened.index.Refs[digestOf("kept")] != 2 {
+		t.Errorf("expected corrected refs, got %+v", reopened.index.Refs)
+	}
+}
+
+func TestCASStore_Forget(t *testing.T) {
+	dir := t.TempDir()
+	store := persistCAS(t, dir, LinkIndex,
+		named("http://example.com/a", "a.bin", "kept"),
+		named("http://example.com/b", "b.bin", "forgotten"),
+		named("http://example.com/c", "c.bin", "kept"),
+	)
+
+	// Index entries stay live while their blob exists, so nothing is collected until one is forgotten.
+	if stats, err := store.GC(true); err != nil || stats != (GCStats{}) {
+		t.Fatalf("expected nothing to collect, got %+v, %v", stats, err)
+	}
+	if store.Forget("missing.bin") {
+		t.Error("expected Forget to report an unknown key")
+	}
+
+	// Forgetting an entry whose blob is shared removes nothing, but is saved.
+	if !store.Forget("c.bin") {
+		t.Fatal("expected Forget to drop c.bin")
+	}
+	if stats, err := store.GC(false); err != nil || stats != (GCStats{}) {
+		t.Fatalf("expected nothing to collect, got %+v, %v", stats, err)
+	}
+	reopened, err := OpenCAS(dir, LinkIndex)
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	if _, ok := reopened.index.Entries["c.bin"]; ok || len(reopened.index.Entries) != 2 {
+		t.Errorf("expected c.bin to stay forgotten, got %+v", reopened.index.Entries)
+	}
+
+	if !store.Forget("b.bin") {
+		t.Fatal("expected Forget to drop b.bin")
+	}
+	stats, err := store.GC(false)
+	if err != nil {
+		t.Fatalf("gc failed: %v", err)
+	}
+	if stats.StaleEntries != 0 || stats.Blobs != 1 || stats.Bytes != int64(len("forgotten")) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if _, err := os.Stat(store.BlobPath(digestOf("forgotten"))); !os.IsNotExist(err) {
+		t.Errorf("expected unreferenced blob to be removed, got %v", err)
+	}
+	if _, err := os.Stat(store.BlobPath(digestOf("kept"))); err != nil {
+		t.Errorf("expected blob still referenced by a.bin to survive gc: %v", err)
+	}
+
+	reopened, err = OpenCAS(dir, LinkIndex)
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	if len(reopened.index.Entries) != 1 || reopened.index.Refs[digestOf("kept")] != 1 {
+		t.Errorf("unexpected index after gc: %+v", reopened.index)
+	}
+}
+
+func TestOpenCAS_InvalidDigest(t *testing.T) {
+	tests := map[string]string{
+		"short entry digest": `{"entries": {"a.bin": {"digest": "ab", "mode": "index"}}, "refs": {}}`,
+		"non-hex ref digest": `{"entries": {}, "refs": {"` + strings.Repeat("z", 64) + `": 1}}`,
+		"uppercase digest":   `{"entries": {"a.bin": {"digest": "` + strings.ToUpper(digestOf("x")) + `", "mode": "index"}}}`,
+	}
+	for name, index := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "cas-index.json"), []byte(index), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := OpenCAS(dir, LinkIndex); err == nil {
+				t.Error("expected an error for an invalid digest, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package persistence
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Outcome is what the persister did with one item.
+type Outcome string
+
+const (
+	OutcomeSaved          Outcome = "saved"           // The payload was saved
+	OutcomeUnchanged      Outcome = "unchanged"       // The server reported the URL unchanged since the previous run
+	OutcomeFailed         Outcome = "failed"          // Downloading or saving failed
+	OutcomeChecksumFailed Outcome = "checksum_failed" // The payload did not match its expected digest and was discarded
+)
+
+// sidecarSuffix is appended to a file's storage key to name its metadata sidecar.
+const sidecarSuffix = ".meta.json"
+
+// sensitiveHeaders are left out of sidecars, which are readable by anyone with access to the downloads,
+// since they carry session cookies or credentials.
+var sensitiveHeaders = []string{"Set-Cookie", "Set-Cookie2", "Cookie", "Authorization", "Proxy-Authorization"}
+
+// Metadata describes how one item was downloaded and persisted. It is written as a sidecar next to
+// each saved file and as one line of the run manifest.
+type Metadata struct {
+	URL          string               `json:"url"`                      // Requested URL
+	Source       string               `json:"source,omitempty"`         // Where the URL was found: input file, sitemap, page or crawled page
+	Line         int                  `json:"line,omitempty"`           // Line of Source the URL was read from
+	Depth        int                  `json:"depth,omitempty"`          // Links followed from a crawl seed to reach URL
+	FinalURL     string               `json:"final_url,omitempty"`      // URL the payload was served from, after redirects
+	Redirects    []models.Redirect    `json:"redirects,omitempty"`      // Redirects followed to reach FinalURL, in order
+	Outcome      Outcome              `json:"outcome"`                  // What happened to the item
+	Error        string               `json:"error,omitempty"`          // Why the item failed
+	Category     models.ErrorCategory `json:"error_category,omitempty"` // Category of Error
+	StatusCode   int                  `json:"status_code,omitempty"`    // HTTP status of the last response
+	Proto        string               `json:"proto,omitempty"`          // Protocol of the last response
+	TLS          *models.TLSInfo      `json:"tls,omitempty"`            // TLS connection details of the last response
+	Header       http.Header          `json:"headers,omitempty"`        // Response headers; sidecars only
+	Path         string               `json:"path,omitempty"`           // Storage key the payload was saved under
+	Size         int64                `json:"size"`                     // Payload size in bytes
+	BytesRead    int64                `json:"bytes_read,omitempty"`     // Body bytes received by the last attempt
+	SHA256       string               `json:"sha256,omitempty"`         // Hex SHA-256 of the saved payload
+	DurationMs   int64                `json:"duration_ms"`              // Download time including retries
+	Attempts     int                  `json:"attempts,omitempty"`       // HTTP attempts made, including retries
+	ETag         string               `json:"etag,omitempty"`           // ETag response header
+	LastModified string               `json:"last_modified,omitempty"`  // Last-Modified response header
+	Time         time.Time            `json:"time"`                     // When the item was persisted
+}
+
+// newMetadata describes c with the given outcome.
+func newMetadata(c models.Content, outcome Outcome) Metadata {
+	m := Metadata{
+		URL:          c.URL,
+		Source:       c.Record.Source,
+		Line:         c.Record.Line,
+		Depth:        c.Record.Depth,
+		FinalURL:     c.FinalURL,
+		Redirects:    c.Redirects,
+		Outcome:      outcome,
+		Category:     c.ErrorCategory,
+		StatusCode:   c.StatusCode,
+		Proto:        c.Proto,
+		TLS:          c.TLS,
+		Header:       redactHeader(c.Header),
+		Size:         c.Size,
+		BytesRead:    c.BytesRead,
+		DurationMs:   c.Duration,
+		Attempts:     c.Attempts,
+		ETag:         c.ETag,
+		LastModified: c.LastModified,
+		Time:         time.Now().UTC(),
+	}
+	if c.Error != nil {
+		m.Error = c.Error.Error()
+	}
+	return m
+}
+
+// redactHeader returns a copy of header without sensitiveHeaders, or header itself if it has none of them.
+func redactHeader(header http.Header) http.Header {
+	var redacted http.Header
+	for _, name := range sensitiveHeaders {
+		if _, ok := header[name]; !ok {
+			continue
+		}
+		if redacted == nil {
+			redacted = header.Clone()
+		}
+		delete(redacted, name)
+	}
+	if redacted == nil {
+		return header
+	}
+	return redacted
+}
+
+// writeSidecar saves m as the sidecar of the file it describes.
+func (fp *FilePersister) writeSidecar(ctx context.Context, m Metadata) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fp.storage.Put(ctx, m.Path+sidecarSuffix, bytes.NewReader(data), int64(len(data)))
+}
+
+// Manifest is a JSON Lines file listing every item of a run with its outcome. It is safe for concurrent use.
+type Manifest struct {
+	mu   sync.Mutex    // Guards w
+	file *os.File      // Underlying file
+	w    *bufio.Writer // Buffered writer over file
+}
+
+// CreateManifest creates, or truncates, the manifest file at path.
+//
+// Parameters:
+//   - path: Path of the JSON Lines file.
+//
+// Returns:
+//   - The manifest, or an error if the file cannot be created.
+func CreateManifest(path string) (*Manifest, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Manifest{file: file, w: bufio.NewWriter(file)}, nil
+}
+
+// Add appends one line describing m, without its headers.
+//
+// Returns:
+//   - An error if the line cannot be written.
+func (m *Manifest) Add(entry Metadata) error {
+	entry.Header = nil
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Close flushes the manifest to disk and closes it.
+//
+// Returns:
+//   - An error if flushing or closing fails.
+func (m *Manifest) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.w.Flush(); err != nil {
+		m.file.Close()
+		return err
+	}
+	if err := m.file.Sync(); err != nil {
+		m.file.Close()
+		return err
+	}
+	return m.file.Close()
+}
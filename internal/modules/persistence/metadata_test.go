@@ -0,0 +1,117 @@
+package persistence
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestFilePersister_SidecarsAndManifest(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "manifest.jsonl")
+
+	manifest, err := CreateManifest(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp := New(dir, WithSidecars(), WithManifest(manifest))
+
+	inputChan := make(chan models.Content, 3)
+	inputChan <- models.Content{
+		URL:        "http://example.com/app.jar",
+		Record:     models.URLRecord{URL: "http://example.com/app.jar", Filename: "app.jar"},
+		FinalURL:   "https://mirror.example.com/app.jar",
+		Redirects:  []models.Redirect{{URL: "http://example.com/app.jar", StatusCode: http.StatusFound, Location: "https://mirror.example.com/app.jar"}},
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/java-archive"}, "Set-Cookie": {"session=secret"}},
+		Data:       []byte("jar"),
+		Size:       3,
+		Duration:   42,
+		Attempts:   2,
+	}
+	inputChan <- models.Content{
+		URL:           "http://example.com/missing",
+		Record:        models.URLRecord{URL: "http://example.com/missing", Source: "urls.csv", Line: 7, Depth: 2},
+		StatusCode:    http.StatusNotFound,
+		Error:         fmt.Errorf("bad status: 404"),
+		ErrorCategory: models.CategoryHTTPStatus,
+	}
+	inputChan <- models.Content{URL: "http://example.com/tampered", Error: fmt.Errorf("%w: expected sha256:00", models.ErrChecksumMismatch), ErrorCategory: models.CategoryChecksum}
+	close(inputChan)
+
+	if err := fp.Execute(context.Background(), inputChan, make(chan struct{}), logger); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manifest.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "app.jar.meta.json"))
+	if err != nil {
+		t.Fatalf("expected sidecar: %v", err)
+	}
+	var sidecar Metadata
+	if err := json.Unmarshal(data, &sidecar); err != nil {
+		t.Fatal(err)
+	}
+	if sidecar.URL != "http://example.com/app.jar" || sidecar.FinalURL != "https://mirror.example.com/app.jar" ||
+		sidecar.StatusCode != http.StatusOK || sidecar.Path != "app.jar" || sidecar.Size != 3 || sidecar.DurationMs != 42 ||
+		sidecar.SHA256 != digestOf("jar") || sidecar.Header.Get("Content-Type") != "application/java-archive" ||
+		len(sidecar.Redirects) != 1 || sidecar.Redirects[0].StatusCode != http.StatusFound {
+		t.Errorf("unexpected sidecar %+v", sidecar)
+	}
+	if sidecar.Header.Get("Set-Cookie") != "" {
+		t.Errorf("expected Set-Cookie to be left out of the sidecar, got %v", sidecar.Header)
+	}
+
+	file, err := os.Open(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var entries []Metadata
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var m Metadata
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			t.Fatalf("malformed manifest line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, m)
+	}
+
+	expected := []struct {
+		url      string
+		outcome  Outcome
+		path     string
+		category models.ErrorCategory
+		source   string
+		line     int
+		depth    int
+	}{
+		{url: "http://example.com/app.jar", outcome: OutcomeSaved, path: "app.jar"},
+		{url: "http://example.com/missing", outcome: OutcomeFailed, category: models.CategoryHTTPStatus, source: "urls.csv", line: 7, depth: 2},
+		{url: "http://example.com/tampered", outcome: OutcomeChecksumFailed, category: models.CategoryChecksum},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d manifest entries, got %+v", len(expected), entries)
+	}
+	for i, e := range expected {
+		got := entries[i]
+		if got.URL != e.url || got.Outcome != e.outcome || got.Path != e.path || got.Category != e.category ||
+			got.Source != e.source || got.Line != e.line || got.Depth != e.depth || (got.Error != "") != (e.category != "") {
+			t.Errorf("entry %d: unexpected %+v", i, got)
+		}
+		if got.Header != nil {
+			t.Errorf("entry %d: expected no headers in the manifest", i)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package persistence
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"jfrog-assignment/internal/models"
+	"mime"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxNameLength = 255 // Longest file or directory name most filesystems accept, in bytes
+
+// Namer decides the path, relative to the download directory, that content is saved under.
+// The path uses forward slashes; each component is sanitized and truncated by the persister.
+type Namer interface {
+	Name(c models.Content) string
+}
+
+// Base64Namer names files after the base64-encoded URL with a .txt extension.
+type Base64Namer struct{}
+
+// Name implements Namer.
+func (Base64Namer) Name(c models.Content) string {
+	return base64.URLEncoding.EncodeToString([]byte(c.URL)) + ".txt"
+}
+
+// HashNamer names files after the SHA-256 hash of the URL, with an inferred extension.
+type HashNamer struct{}
+
+// Name implements Namer.
+func (HashNamer) Name(c models.Content) string {
+	return urlHash(c.URL) + extension(c)
+}
+
+// MirrorNamer mirrors the URL as a host/path directory tree.
+// Paths ending in a slash are saved as "index", and a query string adds a short hash to the name.
+type MirrorNamer struct{}
+
+// Name implements Namer.
+func (MirrorNamer) Name(c models.Content) string {
+	u := parseURL(c.URL)
+	dir, base := splitPath(u)
+	name := strings.TrimSuffix(base, path.Ext(base))
+	if u.RawQuery != "" {
+		name += "_" + urlHash(u.RawQuery)[:8]
+	}
+	return path.Join(hostDir(u), dir, name+extension(c))
+}
+
+// TemplateNamer builds names from a template such as "{host}/{basename}{ext}". Supported placeholders:
+//   - {host}: host name, with ":" before a port replaced by "_"
+//   - {path}: URL path without the leading slash ("index" for directory URLs)
+//   - {dir}: directory part of the URL path
+//   - {basename}: last path segment without its extension
+//   - {ext}: extension inferred from the URL path or Content-Type, including the dot
+//   - {hash}: SHA-256 hash of the URL
+//   - {base64}: base64-encoded URL
+type TemplateNamer struct {
+	template string
+}
+
+var placeholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+var templatePlaceholders = map[string]bool{
+	"{host}": true, "{path}": true, "{dir}": true, "{basename}": true,
+	"{ext}": true, "{hash}": true, "{base64}": true,
+}
+
+// NewTemplateNamer creates a TemplateNamer, rejecting unknown placeholders.
+//
+// Parameters:
+//   - template: The name template.
+//
+// Returns:
+//   - The namer, or an error if the template is empty or uses an unknown placeholder.
+func NewTemplateNamer(template string) (*TemplateNamer, error) {
+	if strings.TrimSpace(template) == "" {
+		return nil, fmt.Errorf("empty name template")
+	}
+	for _, placeholder := range placeholderPattern.FindAllString(template, -1) {
+		if !templatePlaceholders[placeholder] {
+			return nil, fmt.Errorf("unknown placeholder %s in name template %q", placeholder, template)
+		}
+	}
+	return &TemplateNamer{template: template}, nil
+}
+
+// Name implements Namer.
+func (n *TemplateNamer) Name(c models.Content) string {
+	u := parseURL(c.URL)
+	dir, base := splitPath(u)
+	basename := strings.TrimSuffix(base, path.Ext(base))
+
+	return placeholderPattern.ReplaceAllStringFunc(n.template, func(placeholder string) string {
+		switch placeholder {
+		case "{host}":
+			return hostDir(u)
+		case "{path}":
+			return path.Join(dir, base)
+		case "{dir}":
+			return dir
+		case "{basename}":
+			return basename
+		case "{ext}":
+			return extension(c)
+		case "{hash}":
+			return urlHash(c.URL)
+		case "{base64}":
+			return base64.URLEncoding.EncodeToString([]byte(c.URL))
+		}
+		return placeholder
+	})
+}
+
+// NewNamer returns the naming strategy with the given name.
+//
+// Parameters:
+//   - strategy: One of "base64", "sha256", "mirror" or "template".
+//   - template: The name template, used by the "template" strategy.
+//
+// Returns:
+//   - The Namer, or an error if the strategy is unknown or the template is invalid.
+func NewNamer(strategy, template string) (Namer, error) {
+	switch strategy {
+	case "", "base64":
+		return Base64Namer{}, nil
+	case "sha256":
+		return HashNamer{}, nil
+	case "mirror":
+		return MirrorNamer{}, nil
+	case "template":
+		return NewTemplateNamer(template)
+	default:
+		return nil, fmt.Errorf("unknown naming strategy %q (expected base64, sha256, mirror or template)", strategy)
+	}
+}
+
+// parseURL parses rawURL, returning an empty URL if it is malformed.
+func parseURL(rawURL string) *url.URL {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return &url.URL{}
+	}
+	return u
+}
+
+// splitPath splits the unescaped URL path into its directory and last segment,
+// using "index" as the last segment of directory URLs.
+func splitPath(u *url.URL) (string, string) {
+	p := strings.TrimPrefix(u.Path, "/")
+	if p == "" || strings.HasSuffix(p, "/") {
+		return strings.TrimSuffix(p, "/"), "index"
+	}
+	dir, base := path.Split(p)
+	return strings.TrimSuffix(dir, "/"), base
+}
+
+// hostDir returns the URL host as a directory name.
+func hostDir(u *url.URL) string {
+	host := strings.ToLower(u.Host)
+	if host == "" {
+		return "unknown-host"
+	}
+	return strings.ReplaceAll(host, ":", "_")
+}
+
+// urlHash returns the hex-encoded SHA-256 hash of s.
+func urlHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+var extensionPattern = regexp.MustCompile(`^\.[A-Za-z0-9]{1,10}$`)
+
+// preferredExtensions overrides mime.ExtensionsByType for types with several registered extensions.
+var preferredExtensions = map[string]string{
+	"text/html":                ".html",
+	"text/plain":               ".txt",
+	"image/jpeg":               ".jpg",
+	"application/octet-stream": "",
+}
+
+// extension infers a file extension from the URL path, falling back to the Content-Type.
+// It returns an empty string if neither yields a plausible extension.
+func extension(c models.Content) string {
+	if ext := path.Ext(parseURL(c.URL).Path); extensionPattern.MatchString(ext) {
+		return strings.ToLower(ext)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(c.ContentType)
+	if err != nil {
+		return ""
+	}
+	if ext, ok := preferredExtensions[mediaType]; ok {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// sanitizeName makes a single path component safe to use as a file or directory name:
+// separators and control characters are replaced, "." and ".." are rejected, and names longer than
+// maxNameLength are truncated with a hash of the full name appended to keep them unique.
+func sanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '/' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "." || name == ".." {
+		return "_"
+	}
+	if len(name) <= maxNameLength {
+		return name
+	}
+
+	ext := path.Ext(name)
+	if !extensionPattern.MatchString(ext) {
+		ext = ""
+	}
+	suffix := "-" + urlHash(name)[:16] + ext
+	prefix := name[:maxNameLength-len(suffix)]
+	for !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+	return prefix + suffix
+}
+
+// sanitizePath sanitizes every component of a slash-separated relative path, dropping empty components.
+func sanitizePath(p string) string {
+	var parts []string
+	for _, part := range strings.Split(p, "/") {
+		if part != "" {
+			parts = append(parts, sanitizeName(part))
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,89 @@
+package persistence
+
+import (
+	"jfrog-assignment/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestNamers(t *testing.T) {
+	template, err := NewTemplateNamer("{host}/{basename}{ext}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pathTemplate, err := NewTemplateNamer("{host}/{path}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		namer       Namer
+		url         string
+		contentType string
+		expected    string
+	}{
+		{name: "base64", namer: Base64Namer{}, url: "http://example.com", expected: "aHR0cDovL2V4YW1wbGUuY29t.txt"},
+		{name: "sha256 with URL extension", namer: HashNamer{}, url: "http://example.com/app.JAR", expected: urlHash("http://example.com/app.JAR") + ".jar"},
+		{name: "sha256 with Content-Type", namer: HashNamer{}, url: "http://example.com", contentType: "text/html; charset=utf-8", expected: urlHash("http://example.com") + ".html"},
+		{name: "sha256 with unknown type", namer: HashNamer{}, url: "http://example.com/data", contentType: "application/octet-stream", expected: urlHash("http://example.com/data")},
+		{name: "mirror file", namer: MirrorNamer{}, url: "http://Example.com:8080/repo/libs/app-1.0.tar.gz", expected: "example.com_8080/repo/libs/app-1.0.tar.gz"},
+		{name: "mirror directory", namer: MirrorNamer{}, url: "http://example.com/docs/", contentType: "text/html", expected: "example.com/docs/index.html"},
+		{name: "mirror root", namer: MirrorNamer{}, url: "http://www.google.com", contentType: "text/html", expected: "www.google.com/index.html"},
+		{name: "mirror query", namer: MirrorNamer{}, url: "http://example.com/search?q=go", contentType: "text/html", expected: "example.com/search_" + urlHash("q=go")[:8] + ".html"},
+		{name: "template basename", namer: template, url: "http://example.com/a/b/report.pdf", expected: "example.com/report.pdf"},
+		{name: "template path", namer: pathTemplate, url: "http://example.com/a/b/report.pdf", expected: "example.com/a/b/report.pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.namer.Name(models.Content{URL: tt.url, ContentType: tt.contentType})
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewNamer(t *testing.T) {
+	for _, strategy := range []string{"", "base64", "sha256", "mirror", "template"} {
+		if _, err := NewNamer(strategy, "{hash}{ext}"); err != nil {
+			t.Errorf("strategy %q: unexpected error: %v", strategy, err)
+		}
+	}
+	if _, err := NewNamer("random", ""); err == nil {
+		t.Errorf("expected error for unknown strategy")
+	}
+	if _, err := NewNamer("template", "{host}/{filename}"); err == nil {
+		t.Errorf("expected error for unknown placeholder")
+	}
+}
+
+func TestSanitizePath(t *testing.T) {
+	long := strings.Repeat("a", 300) + ".tar"
+
+	tests := []struct {
+		name  string
+		input string
+		check func(string) bool
+	}{
+		{name: "dot segments", input: "../x/./y", check: func(s string) bool { return s == "_/x/_/y" }},
+		{name: "control characters", input: "a\x00b\\c", check: func(s string) bool { return s == "a_b_c" }},
+		{name: "empty segments", input: "/a//b/", check: func(s string) bool { return s == "a/b" }},
+		{name: "long name", input: "dir/" + long, check: func(s string) bool {
+			base := strings.TrimPrefix(s, "dir/")
+			return len(base) <= maxNameLength && strings.HasSuffix(base, ".tar") && strings.HasPrefix(base, "aaaa")
+		}},
+		{name: "long names stay unique", input: long, check: func(s string) bool {
+			return s != sanitizePath(strings.Repeat("a", 301)+".tar")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizePath(tt.input); !tt.check(got) {
+				t.Errorf("unexpected result %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package persistence
+
+import "jfrog-assignment/internal/modules/storage"
+
+// Option configures a FilePersister.
+type Option func(*FilePersister)
+
+// WithSyncState makes the persister record the validators of every persisted URL in state,
+// and save it when the stage finishes, so later runs can skip unchanged URLs.
+//
+// Parameters:
+//   - state: The sync state to update.
+//
+// Returns:
+//   - An Option enabling sync state tracking.
+func WithSyncState(state *SyncState) Option {
+	return func(fp *FilePersister) {
+		fp.state = state
+	}
+}
+
+// WithNamer sets the strategy naming files that have no explicit file name. Base64Namer is used by default.
+//
+// Parameters:
+//   - namer: The naming strategy.
+//
+// Returns:
+//   - An Option setting the naming strategy.
+func WithNamer(namer Namer) Option {
+	return func(fp *FilePersister) {
+		fp.namer = namer
+	}
+}
+
+// WithCAS stores payloads in a content-addressable store, so identical payloads are written only once
+// and every persisted file refers to the shared blob. The store's root should be the download directory.
+//
+// Parameters:
+//   - store: The store to persist into; see OpenCAS.
+//
+// Returns:
+//   - An Option enabling content-addressable storage.
+func WithCAS(store *CASStore) Option {
+	return func(fp *FilePersister) {
+		fp.cas = store
+	}
+}
+
+// WithStorage saves files to the given backend instead of the download directory passed to New.
+//
+// Parameters:
+//   - st: The storage backend.
+//
+// Returns:
+//   - An Option setting the storage backend.
+func WithStorage(st storage.Storage) Option {
+	return func(fp *FilePersister) {
+		fp.storage = st
+	}
+}
+
+// WithSidecars saves a JSON metadata sidecar (see Metadata) next to every saved file, named after the file
+// with a ".meta.json" suffix.
+//
+// Returns:
+//   - An Option enabling metadata sidecars.
+func WithSidecars() Option {
+	return func(fp *FilePersister) {
+		fp.sidecars = true
+	}
+}
+
+// WithManifest appends one line per item, whatever its outcome, to the given run manifest.
+// The caller closes the manifest once the pipeline has finished.
+//
+// Parameters:
+//   - manifest: The manifest to write to; see CreateManifest.
+//
+// Returns:
+//   - An Option enabling the run manifest.
+func WithManifest(manifest *Manifest) Option {
+	return func(fp *FilePersister) {
+		fp.manifest = manifest
+	}
+}
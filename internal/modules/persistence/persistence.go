@@ -1,89 +1,269 @@
 package persistence
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
 	"jfrog-assignment/internal/models"
-	"os"
+	"jfrog-assignment/internal/modules/pipeline"
+	"jfrog-assignment/internal/modules/storage"
 	"path/filepath"
 
 	"go.uber.org/zap"
 )
 
-// FilePersister implements both ContentPersister and pipeline.Stage for saving downloaded content to files.
+// FilePersister implements both ContentPersister and pipeline.Stage for saving downloaded content to a storage backend.
 type FilePersister struct {
-	downloadDir string // Directory where files are saved
+	storage  storage.Storage // Backend files are saved to
+	namer    Namer           // Strategy naming files that have no explicit file name
+	state    *SyncState      // Validators of persisted URLs for incremental syncs, if enabled
+	cas      *CASStore       // Content-addressable store deduplicating payloads, if enabled
+	sidecars bool            // Whether a metadata sidecar is saved next to each file
+	manifest *Manifest       // Manifest listing the outcome of every item, if enabled
 }
 
-const defaultDownloadDir = "./downloads" // Default directory for saving files
+// tempRemover is implemented by storage backends that can clean up after interrupted writes, such as storage.Local.
+type tempRemover interface {
+	RemoveTemp() (int, error)
+}
+
+// DefaultDownloadDir is the directory files are saved to when none is given.
+const DefaultDownloadDir = "./downloads"
 
-// New creates a new FilePersister instance with an optional custom directory.
+var _ pipeline.Stage[models.Content, struct{}] = (*FilePersister)(nil)
+
+// New creates a new FilePersister instance saving files to the given directory.
 //
 // Parameters:
-//   - downloadDir: Optional variadic parameter for the directory path. Uses defaultDownloadDir if not provided.
+//   - downloadDir: The directory path. Uses DefaultDownloadDir if empty.
+//   - opts: Optional settings; WithStorage replaces the directory with another backend.
 //
 // Returns:
 //   - A pointer to a new FilePersister instance.
-func New(downloadDir ...string) *FilePersister {
-	dir := defaultDownloadDir
-	if len(downloadDir) > 0 {
-		dir = downloadDir[0]
+func New(downloadDir string, opts ...Option) *FilePersister {
+	if downloadDir == "" {
+		downloadDir = DefaultDownloadDir
+	}
+	fp := &FilePersister{storage: storage.NewLocal(downloadDir), namer: Base64Namer{}}
+	for _, opt := range opts {
+		opt(fp)
 	}
-	return &FilePersister{downloadDir: dir}
+	if fp.state != nil {
+		// Persisted files are looked up where they were saved.
+		fp.state.store = fp.storage
+	}
+	return fp
 }
 
 // Execute saves content received on the input channel to files as part of the pipeline.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeouts.
-//   - input: Channel to receive content from as interface{}.
+//   - input: Channel to receive content from.
 //   - output: Output channel (unused, persistence is the final stage).
 //   - logger: Logger for logging progress and errors.
 //
 // Returns:
 //   - An error if persistence fails, nil otherwise.
-func (fp *FilePersister) Execute(ctx context.Context, input <-chan interface{}, output chan<- interface{}, logger *zap.Logger) error {
-	if err := os.MkdirAll(fp.downloadDir, 0755); err != nil {
-		return err
+func (fp *FilePersister) Execute(ctx context.Context, input <-chan models.Content, output chan<- struct{}, logger *zap.Logger) error {
+	if cleaner, ok := fp.storage.(tempRemover); ok {
+		removed, err := cleaner.RemoveTemp()
+		if err != nil {
+			return err
+		}
+		if removed > 0 {
+			logger.Info("removed temporary files left by an interrupted run", zap.Int("files", removed))
+		}
 	}
 
 	successCount := 0
+	unchangedCount := 0
+	checksumCount := 0
 	failCount := 0
 
-	for content := range input {
+	for c := range input {
 		select {
 		case <-ctx.Done():
+			closeBody(c)
 			logger.Warn("persistence interrupted", zap.Error(ctx.Err()))
+			// Keep what was persisted so far for the next run.
+			if fp.state != nil {
+				if err := fp.state.Save(); err != nil {
+					logger.Warn("saving sync state failed", zap.Error(err))
+				}
+			}
+			if fp.cas != nil {
+				if err := fp.cas.Save(); err != nil {
+					logger.Warn("saving CAS index failed", zap.Error(err))
+				}
+			}
+			// Release the streamed bodies still on their way, so their spool files are removed.
+			for rest := range input {
+				closeBody(rest)
+			}
 			return ctx.Err()
 		default:
-			c, ok := content.(models.Content)
-			if !ok {
-				logger.Warn("invalid input type, expected Content", zap.Any("type", content))
-				continue
-			}
-			if c.Error != nil {
+			m := fp.persist(ctx, c, logger)
+			switch m.Outcome {
+			case OutcomeSaved:
+				successCount++
+			case OutcomeUnchanged:
+				unchangedCount++
+			case OutcomeChecksumFailed:
+				checksumCount++
+			default:
 				failCount++
-				continue
 			}
-
-			filename := base64.URLEncoding.EncodeToString([]byte(c.URL)) + ".txt"
-			filepath := filepath.Join(fp.downloadDir, filename)
-
-			logger.Debug("persisting file", zap.String("filepath", filepath))
-			if err := os.WriteFile(filepath, c.Data, 0644); err != nil {
-				logger.Warn("persist failed",
-					zap.String("url", c.URL),
-					zap.String("filepath", filepath),
-					zap.Error(err))
-				failCount++
-				continue
+			if fp.manifest != nil {
+				if err := fp.manifest.Add(m); err != nil {
+					logger.Warn("writing manifest failed", zap.String("url", c.URL), zap.Error(err))
+				}
 			}
-			successCount++
+		}
+	}
+
+	if fp.state != nil {
+		if err := fp.state.Save(); err != nil {
+			return err
+		}
+	}
+	if fp.cas != nil {
+		if err := fp.cas.Save(); err != nil {
+			return err
 		}
 	}
 
 	logger.Info("persistence statistics",
 		zap.Int("successful", successCount),
+		zap.Int("unchanged", unchangedCount),
+		zap.Int("checksum_failed", checksumCount),
 		zap.Int("failed", failCount))
 	return nil
 }
+
+// persist saves the payload of one item, unless its download failed or it is unchanged, and writes its sidecar.
+//
+// Parameters:
+//   - ctx: Context for cancellation of remote uploads.
+//   - c: The content to persist.
+//   - logger: Logger for logging failures.
+//
+// Returns:
+//   - Metadata describing the item and its outcome.
+func (fp *FilePersister) persist(ctx context.Context, c models.Content, logger *zap.Logger) Metadata {
+	if errors.Is(c.Error, models.ErrChecksumMismatch) {
+		logger.Warn("not persisting content that failed checksum verification",
+			zap.String("url", c.URL),
+			zap.Error(c.Error))
+		closeBody(c)
+		return newMetadata(c, OutcomeChecksumFailed)
+	}
+	if c.Error != nil {
+		closeBody(c)
+		return newMetadata(c, OutcomeFailed)
+	}
+	if c.NotModified {
+		m := newMetadata(c, OutcomeUnchanged)
+		if fp.state != nil {
+			m.Path = fp.state.pathOf(c.URL)
+		}
+		return m
+	}
+
+	m := newMetadata(c, OutcomeSaved)
+	key, err := fp.targetKey(c)
+	if err != nil {
+		logger.Warn("persist failed", zap.String("url", c.URL), zap.Error(err))
+		closeBody(c)
+		m.Outcome, m.Error, m.Category = OutcomeFailed, err.Error(), models.CategoryStorage
+		return m
+	}
+	m.Path = key
+
+	logger.Debug("persisting file", zap.String("key", key))
+	readKey := key
+	if fp.cas != nil {
+		readKey, m.SHA256, err = fp.cas.put(key, c)
+	} else {
+		m.SHA256, err = fp.put(ctx, key, c)
+	}
+	if err != nil {
+		logger.Warn("persist failed",
+			zap.String("url", c.URL),
+			zap.String("key", key),
+			zap.Error(err))
+		m.Outcome, m.Error, m.Category = OutcomeFailed, err.Error(), models.CategoryStorage
+		return m
+	}
+
+	if fp.state != nil {
+		fp.state.record(c.URL, c.ETag, c.LastModified, readKey)
+	}
+	if fp.sidecars {
+		if err := fp.writeSidecar(ctx, m); err != nil {
+			logger.Warn("writing sidecar failed", zap.String("key", key), zap.Error(err))
+		}
+	}
+	return m
+}
+
+// targetKey returns the storage key c is saved under: the record's file name inside its subdirectory,
+// with the name chosen by the naming strategy as the default file name.
+//
+// Parameters:
+//   - c: The content to save.
+//
+// Returns:
+//   - The slash-separated key, or an error if the record's subdirectory or file name escapes the download directory.
+func (fp *FilePersister) targetKey(c models.Content) (string, error) {
+	name := c.Record.Filename
+	if name == "" {
+		name = fp.namer.Name(c)
+	}
+	rel := filepath.Join(filepath.FromSlash(c.Record.Subdir), filepath.FromSlash(sanitizePath(name)))
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("output path %q escapes the download directory", rel)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// put saves the payload of c under key. A streamed body is copied incrementally and closed.
+//
+// Parameters:
+//   - ctx: Context for cancellation of remote uploads.
+//   - key: Destination storage key.
+//   - c: The content to save.
+//
+// Returns:
+//   - The hex SHA-256 of the saved payload, or an error if saving fails.
+func (fp *FilePersister) put(ctx context.Context, key string, c models.Content) (string, error) {
+	if c.Body == nil {
+		if err := fp.storage.Put(ctx, key, bytes.NewReader(c.Data), int64(len(c.Data))); err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256(c.Data)
+		return hex.EncodeToString(sum[:]), nil
+	}
+	defer c.Body.Close()
+
+	size := c.Size
+	if size <= 0 {
+		size = -1 // Not reported by the producer of the body.
+	}
+	h := sha256.New()
+	if err := fp.storage.Put(ctx, key, io.TeeReader(c.Body, h), size); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// closeBody releases the streamed body of c, if any.
+func closeBody(c models.Content) {
+	if c.Body != nil {
+		c.Body.Close()
+	}
+}
@@ -2,9 +2,15 @@ package persistence
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/storage"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"go.uber.org/zap/zaptest"
@@ -36,6 +42,22 @@ func TestFilePersister_Execute(t *testing.T) {
 			expectErr:   false,
 			expectFiles: 1,
 		},
+		{
+			name: "checksum mismatch",
+			contents: []models.Content{
+				{URL: "http://example.com", Data: []byte("tampered"), Error: fmt.Errorf("%w: expected sha256:00", models.ErrChecksumMismatch)},
+			},
+			expectErr:   false,
+			expectFiles: 0,
+		},
+		{
+			name: "streamed content",
+			contents: []models.Content{
+				{URL: "http://example.com", Body: io.NopCloser(strings.NewReader("streamed data")), Size: 13},
+			},
+			expectErr:   false,
+			expectFiles: 1,
+		},
 		{
 			name:        "empty content",
 			contents:    []models.Content{},
@@ -52,13 +74,13 @@ func TestFilePersister_Execute(t *testing.T) {
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
-			inputChan := make(chan interface{}, len(tt.contents))
+			inputChan := make(chan models.Content, len(tt.contents))
 			for _, content := range tt.contents {
 				inputChan <- content
 			}
 			close(inputChan)
 
-			outputChan := make(chan interface{}, 1) // Not used, but required by interface
+			outputChan := make(chan struct{}, 1) // Not used, but required by interface
 			done := make(chan error)
 			go func() {
 				done <- fp.Execute(ctx, inputChan, outputChan, logger)
@@ -80,3 +102,168 @@ func TestFilePersister_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestFilePersister_PutStreamed(t *testing.T) {
+	dir := t.TempDir()
+	fp := New(dir)
+	c := models.Content{URL: "http://example.com", Body: io.NopCloser(strings.NewReader("streamed data"))}
+
+	digest, err := fp.put(context.Background(), "out.txt", c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sum := sha256.Sum256([]byte("streamed data")); digest != hex.EncodeToString(sum[:]) {
+		t.Errorf("unexpected digest %s", digest)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "streamed data" {
+		t.Errorf("expected streamed data, got %q", data)
+	}
+}
+
+// trackedBody is a streamed body counting how often it was closed.
+type trackedBody struct {
+	io.Reader
+	closed *int
+}
+
+func (b trackedBody) Close() error {
+	*b.closed++
+	return nil
+}
+
+func TestFilePersister_CanceledClosesBodies(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	fp := New(t.TempDir())
+
+	closed := 0
+	inputChan := make(chan models.Content, 3)
+	for i := 0; i < 3; i++ {
+		inputChan <- models.Content{
+			URL:  fmt.Sprintf("http://example.com/%d", i),
+			Body: trackedBody{Reader: strings.NewReader("streamed data"), closed: &closed},
+		}
+	}
+	close(inputChan)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := fp.Execute(ctx, inputChan, make(chan struct{}), logger); err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if closed != 3 {
+		t.Errorf("expected every queued body to be closed, %d of 3 were", closed)
+	}
+}
+
+func TestFilePersister_RecordPath(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	tmpDir := t.TempDir()
+	fp := New(tmpDir)
+
+	inputChan := make(chan models.Content, 2)
+	inputChan <- models.Content{
+		URL:    "http://example.com/a",
+		Record: models.URLRecord{URL: "http://example.com/a", Filename: "a.bin", Subdir: "nightly/x86"},
+		Data:   []byte("a"),
+	}
+	inputChan <- models.Content{
+		URL:    "http://example.com/b",
+		Record: models.URLRecord{URL: "http://example.com/b", Filename: "b.bin", Subdir: "../outside"},
+		Data:   []byte("b"),
+	}
+	close(inputChan)
+
+	if err := fp.Execute(context.Background(), inputChan, make(chan struct{}), logger); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(tmpDir, "nightly", "x86", "a.bin")); err != nil || string(data) != "a" {
+		t.Errorf("expected a.bin in subdirectory, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(tmpDir), "outside", "b.bin")); err == nil {
+		t.Errorf("expected path escaping the download directory to be rejected")
+	}
+}
+
+// memStorage is an in-memory storage.Storage recording the objects it is given.
+type memStorage struct {
+	storage.Storage
+	objects map[string]string
+}
+
+func (m *memStorage) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	data, err := io.ReadAll(r)
+	m.objects[key] = string(data)
+	return err
+}
+
+func (m *memStorage) Stat(ctx context.Context, key string) (storage.ObjectInfo, error) {
+	data, ok := m.objects[key]
+	if !ok {
+		return storage.ObjectInfo{}, storage.ErrNotFound
+	}
+	return storage.ObjectInfo{Key: key, Size: int64(len(data))}, nil
+}
+
+func TestFilePersister_Storage(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	dir := t.TempDir()
+	store := &memStorage{objects: make(map[string]string)}
+
+	state, err := LoadSyncState(filepath.Join(dir, ".sync-state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp := New(dir, WithStorage(store), WithSyncState(state))
+
+	inputChan := make(chan models.Content, 2)
+	inputChan <- models.Content{
+		URL:    "http://example.com/a",
+		Record: models.URLRecord{URL: "http://example.com/a", Filename: "a.bin", Subdir: "nightly"},
+		Data:   []byte("a"),
+		ETag:   `"a"`,
+	}
+	inputChan <- models.Content{URL: "http://example.com/b", Body: io.NopCloser(strings.NewReader("streamed")), Size: 8}
+	close(inputChan)
+
+	if err := fp.Execute(context.Background(), inputChan, make(chan struct{}), logger); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if store.objects["nightly/a.bin"] != "a" || store.objects[Base64Namer{}.Name(models.Content{URL: "http://example.com/b"})] != "streamed" {
+		t.Errorf("unexpected stored objects %v", store.objects)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.txt")); len(files) != 0 {
+		t.Errorf("expected nothing written to the download directory, got %v", files)
+	}
+	if etag, _, ok := state.Validators("http://example.com/a"); !ok || etag != `"a"` {
+		t.Errorf("expected validators looked up in the storage, got %q, %v", etag, ok)
+	}
+}
+
+func TestFilePersister_RemovesStaleTemp(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	dir := t.TempDir()
+
+	// A temporary file left by a run that was killed mid-write.
+	stale := filepath.Join(dir, "example.com", ".urldownloader-123.tmp")
+	if err := os.MkdirAll(filepath.Dir(stale), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("trunc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	inputChan := make(chan models.Content)
+	close(inputChan)
+	if err := New(dir).Execute(context.Background(), inputChan, make(chan struct{}), logger); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("expected stale temporary file to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jfrog-assignment/internal/modules/storage"
+	"os"
+	"sync"
+)
+
+// syncEntry is the record kept for a URL persisted by an earlier run.
+type syncEntry struct {
+	ETag         string `json:"etag,omitempty"`          // ETag of the persisted response
+	LastModified string `json:"last_modified,omitempty"` // Last-Modified of the persisted response
+	Path         string `json:"path"`                    // Storage key, or file path without a storage, the response was persisted to
+}
+
+// SyncState records the cache validators of persisted URLs so later runs can send conditional requests.
+// It is safe for concurrent use and implements downloader.ValidatorSource.
+type SyncState struct {
+	path    string               // File the state is loaded from and saved to
+	store   storage.Storage      // Storage persisted files are looked up in; the local file system if nil
+	mu      sync.Mutex           // Guards entries
+	entries map[string]syncEntry // Records keyed by URL
+}
+
+// LoadSyncState reads the sync state stored at path. A missing file yields an empty state.
+//
+// Parameters:
+//   - path: Path of the JSON state file.
+//
+// Returns:
+//   - The loaded state, or an error if the file exists but cannot be read or parsed.
+func LoadSyncState(path string) (*SyncState, error) {
+	s := &SyncState{path: path, entries: make(map[string]syncEntry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, fmt.Errorf("parse sync state %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Validators returns the ETag and Last-Modified recorded for url.
+// URLs whose persisted file has since been removed are reported as unknown so they are downloaded again.
+func (s *SyncState) Validators(url string) (etag, lastModified string, ok bool) {
+	s.mu.Lock()
+	entry, found := s.entries[url]
+	s.mu.Unlock()
+
+	if !found {
+		return "", "", false
+	}
+	if !s.exists(entry.Path) {
+		return "", "", false
+	}
+	return entry.ETag, entry.LastModified, true
+}
+
+// pathOf returns the storage key url was last persisted under, or "" if it is unknown.
+func (s *SyncState) pathOf(url string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries[url].Path
+}
+
+// exists reports whether the file persisted under path is still present.
+func (s *SyncState) exists(path string) bool {
+	if s.store == nil {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+	_, err := s.store.Stat(context.Background(), path)
+	return err == nil
+}
+
+// record stores the validators of a freshly persisted URL.
+// URLs without validators are forgotten, since they cannot be requested conditionally.
+func (s *SyncState) record(url, etag, lastModified, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if etag == "" && lastModified == "" {
+		delete(s.entries, url)
+		return
+	}
+	s.entries[url] = syncEntry{ETag: etag, LastModified: lastModified, Path: path}
+}
+
+// Save writes the state back to the file it was loaded from.
+//
+// Returns:
+//   - An error if the file cannot be written, nil otherwise.
+func (s *SyncState) Save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return storage.WriteFileAtomic(s.path, bytes.NewReader(data), 0644)
+}
@@ -0,0 +1,81 @@
+package persistence
+
+import (
+	"context"
+	"jfrog-assignment/internal/models"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSyncState_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "state.json")
+
+	state, err := LoadSyncState(statePath)
+	if err != nil {
+		t.Fatalf("loading missing state failed: %v", err)
+	}
+
+	kept := filepath.Join(dir, "kept.txt")
+	if err := os.WriteFile(kept, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state.record("http://kept.example", `"a"`, "", kept)
+	state.record("http://removed.example", "", "Mon, 01 Jan 2024 00:00:00 GMT", filepath.Join(dir, "removed.txt"))
+	state.record("http://novalidators.example", "", "", kept)
+	if err := state.Save(); err != nil {
+		t.Fatalf("saving state failed: %v", err)
+	}
+
+	loaded, err := LoadSyncState(statePath)
+	if err != nil {
+		t.Fatalf("loading state failed: %v", err)
+	}
+
+	if etag, _, ok := loaded.Validators("http://kept.example"); !ok || etag != `"a"` {
+		t.Errorf("expected ETag for kept URL, got %q, %v", etag, ok)
+	}
+	if _, _, ok := loaded.Validators("http://removed.example"); ok {
+		t.Errorf("expected URL whose file is missing to be unknown")
+	}
+	if _, _, ok := loaded.Validators("http://novalidators.example"); ok {
+		t.Errorf("expected URL without validators to be unknown")
+	}
+}
+
+func TestFilePersister_SyncState(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	dir := t.TempDir()
+
+	state, err := LoadSyncState(filepath.Join(dir, ".sync-state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp := New(dir, WithSyncState(state))
+
+	inputChan := make(chan models.Content, 2)
+	inputChan <- models.Content{URL: "http://changed.example", Data: []byte("new"), ETag: `"v2"`}
+	inputChan <- models.Content{URL: "http://unchanged.example", NotModified: true}
+	close(inputChan)
+
+	if err := fp.Execute(context.Background(), inputChan, make(chan struct{}), logger); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if len(files) != 1 {
+		t.Errorf("expected only the changed URL to be written, got %v", files)
+	}
+
+	loaded, err := LoadSyncState(filepath.Join(dir, ".sync-state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	New(dir, WithSyncState(loaded)) // Entries record storage keys, looked up in the persister's storage.
+	if etag, _, ok := loaded.Validators("http://changed.example"); !ok || etag != `"v2"` {
+		t.Errorf("expected saved ETag for changed URL, got %q, %v", etag, ok)
+	}
+}
@@ -0,0 +1,126 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// legacyAdapter exposes a LegacyStage as a typed Stage.
+type legacyAdapter[In, Out any] struct {
+	stage LegacyStage
+}
+
+// Adapt wraps an untyped stage so it can be used in a Chain.
+// Values the wrapped stage emits that are not of type Out are logged and dropped.
+//
+// Parameters:
+//   - stage: The untyped stage to wrap.
+//
+// Returns:
+//   - A typed Stage delegating to stage.
+func Adapt[In, Out any](stage LegacyStage) Stage[In, Out] {
+	return &legacyAdapter[In, Out]{stage: stage}
+}
+
+// Execute forwards typed input to the wrapped stage and type-checks its output.
+func (a *legacyAdapter[In, Out]) Execute(ctx context.Context, input <-chan In, output chan<- Out, logger *zap.Logger) error {
+	in := make(chan interface{}, bufferSize)
+	out := make(chan interface{}, bufferSize)
+	stop := make(chan struct{})
+
+	go func() {
+		defer close(in)
+		for item := range input {
+			select {
+			case in <- item:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for item := range out {
+			typed, ok := item.(Out)
+			if !ok {
+				logger.Warn("invalid output type from adapted stage",
+					zap.String("expected", typeName[Out]()),
+					zap.Any("type", item))
+				continue
+			}
+			output <- typed
+		}
+	}()
+
+	err := a.stage.Execute(ctx, in, out, logger)
+	close(stop)
+	close(out)
+	<-done
+	return err
+}
+
+// typedEraser exposes a typed Stage as a LegacyStage.
+type typedEraser[In, Out any] struct {
+	stage Stage[In, Out]
+}
+
+// Erase wraps a typed stage so it can be added to an untyped Pipeline.
+// Input values that are not of type In are logged and dropped.
+//
+// Parameters:
+//   - stage: The typed stage to wrap.
+//
+// Returns:
+//   - An untyped stage delegating to stage.
+func Erase[In, Out any](stage Stage[In, Out]) LegacyStage {
+	return &typedEraser[In, Out]{stage: stage}
+}
+
+// Execute type-checks untyped input and forwards the wrapped stage's output.
+func (e *typedEraser[In, Out]) Execute(ctx context.Context, input <-chan interface{}, output chan<- interface{}, logger *zap.Logger) error {
+	in := make(chan In, bufferSize)
+	out := make(chan Out, bufferSize)
+	stop := make(chan struct{})
+
+	go func() {
+		defer close(in)
+		for item := range input {
+			typed, ok := item.(In)
+			if !ok {
+				logger.Warn("invalid input type for typed stage",
+					zap.String("expected", typeName[In]()),
+					zap.Any("type", item))
+				continue
+			}
+			select {
+			case in <- typed:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for item := range out {
+			output <- item
+		}
+	}()
+
+	err := e.stage.Execute(ctx, in, out, logger)
+	close(stop)
+	close(out)
+	<-done
+	return err
+}
+
+// typeName returns a printable name for T, used in log messages.
+func typeName[T any]() string {
+	var zero T
+	return fmt.Sprintf("%T", &zero)[1:]
+}
@@ -0,0 +1,65 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestAdapt(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	// Legacy stage emitting both valid ints and a value of the wrong type.
+	legacy := &mockStage{
+		process: func(ctx context.Context, input interface{}) (interface{}, error) {
+			if n, ok := input.(int); ok && n < 0 {
+				return "not an int", nil
+			}
+			return input, nil
+		},
+	}
+	sink := &collectStage[int]{}
+	c := Then(NewChain(logger, Adapt[int, int](legacy)), sink)
+
+	inputChan := make(chan int, 3)
+	inputChan <- 1
+	inputChan <- -1
+	inputChan <- 2
+	close(inputChan)
+
+	if err := c.Run(context.Background(), inputChan); err != nil {
+		t.Fatalf("chain execution failed: %v", err)
+	}
+
+	if len(sink.items) != 2 || sink.items[0] != 1 || sink.items[1] != 2 {
+		t.Errorf("expected [1 2], got %v", sink.items)
+	}
+}
+
+func TestErase(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	double := &typedStage[int, int]{process: func(n int) int { return n * 2 }}
+	erased := Erase[int, int](double)
+
+	inputChan := make(chan interface{}, 3)
+	outputChan := make(chan interface{}, 3)
+	inputChan <- 1
+	inputChan <- "skipped"
+	inputChan <- 2
+	close(inputChan)
+
+	if err := erased.Execute(context.Background(), inputChan, outputChan, logger); err != nil {
+		t.Fatalf("erased stage failed: %v", err)
+	}
+	close(outputChan)
+
+	var results []interface{}
+	for item := range outputChan {
+		results = append(results, item)
+	}
+	if len(results) != 2 || results[0] != 2 || results[1] != 4 {
+		t.Errorf("expected [2 4], got %v", results)
+	}
+}
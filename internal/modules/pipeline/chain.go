@@ -0,0 +1,78 @@
+package pipeline
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Chain is a sequence of typed stages that turns In values into Out values.
+//
+// A Chain can only be extended with Then, whose type parameters force each stage's input type
+// to match the previous stage's output type, so mismatched stage chains fail to compile.
+type Chain[In, Out any] struct {
+	logger *zap.Logger // Logger for pipeline-wide logging
+	policy ErrorPolicy // How stage errors affect the other stages
+	wire   func(in <-chan In) (<-chan Out, []stageFunc)
+}
+
+// NewChain creates a new Chain starting with the given stage.
+//
+// Parameters:
+//   - logger: Logger for logging pipeline events.
+//   - first: The first stage of the chain.
+//
+// Returns:
+//   - A pointer to a new Chain instance.
+func NewChain[In, Out any](logger *zap.Logger, first Stage[In, Out]) *Chain[In, Out] {
+	return &Chain[In, Out]{
+		logger: logger,
+		wire: func(in <-chan In) (<-chan Out, []stageFunc) {
+			out := make(chan Out, bufferSize)
+			return out, []stageFunc{bind(first, in, out, logger, false)}
+		},
+	}
+}
+
+// Then returns a new Chain that feeds the output of c into next.
+//
+// Parameters:
+//   - c: The chain to extend.
+//   - next: The stage consuming the chain's output.
+//
+// Returns:
+//   - A pointer to a new Chain producing next's output type.
+func Then[In, Mid, Out any](c *Chain[In, Mid], next Stage[Mid, Out]) *Chain[In, Out] {
+	return &Chain[In, Out]{
+		logger: c.logger,
+		policy: c.policy,
+		wire: func(in <-chan In) (<-chan Out, []stageFunc) {
+			mid, funcs := c.wire(in)
+			out := make(chan Out, bufferSize)
+			return out, append(funcs, bind(next, mid, out, c.logger, true))
+		},
+	}
+}
+
+// SetErrorPolicy sets how the chain reacts to a failing stage. The default is FailFast.
+//
+// Parameters:
+//   - policy: The error policy to apply on the next Run.
+func (c *Chain[In, Out]) SetErrorPolicy(policy ErrorPolicy) {
+	c.policy = policy
+}
+
+// Run executes the chain with the given input channel, discarding the last stage's output.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - input: Initial input channel for the first stage.
+//
+// Returns:
+//   - ctx.Err() if ctx is canceled, once every stage has returned or a grace period has passed, an error
+//     joining one StageError per failed stage if any stage fails, nil otherwise.
+func (c *Chain[In, Out]) Run(ctx context.Context, input <-chan In) error {
+	out, funcs := c.wire(input)
+	go drain(out)
+	return execute(ctx, c.logger, c.policy, funcs)
+}
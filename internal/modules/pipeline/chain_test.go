@@ -0,0 +1,93 @@
+package pipeline
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+type typedStage[In, Out any] struct {
+	process func(in In) Out
+}
+
+func (s *typedStage[In, Out]) Execute(ctx context.Context, input <-chan In, output chan<- Out, logger *zap.Logger) error {
+	for item := range input {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			output <- s.process(item)
+		}
+	}
+	return nil
+}
+
+type collectStage[T any] struct {
+	items []T
+}
+
+func (s *collectStage[T]) Execute(ctx context.Context, input <-chan T, output chan<- struct{}, logger *zap.Logger) error {
+	for item := range input {
+		s.items = append(s.items, item)
+	}
+	return nil
+}
+
+func TestChain_Run(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	double := &typedStage[int, int]{process: func(n int) int { return n * 2 }}
+	format := &typedStage[int, string]{process: func(n int) string { return strconv.Itoa(n) }}
+	sink := &collectStage[string]{}
+
+	c := Then(Then(NewChain[int, int](logger, double), format), sink)
+
+	inputChan := make(chan int, 3)
+	inputChan <- 1
+	inputChan <- 2
+	inputChan <- 3
+	close(inputChan)
+
+	if err := c.Run(context.Background(), inputChan); err != nil {
+		t.Fatalf("chain execution failed: %v", err)
+	}
+
+	expected := []string{"2", "4", "6"}
+	if len(sink.items) != len(expected) {
+		t.Fatalf("expected %d items, got %d: %v", len(expected), len(sink.items), sink.items)
+	}
+	for i, item := range sink.items {
+		if item != expected[i] {
+			t.Errorf("expected %s at index %d, got %s", expected[i], i, item)
+		}
+	}
+}
+
+func TestChain_Cancel(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	slow := &typedStage[int, int]{process: func(n int) int {
+		time.Sleep(100 * time.Millisecond) // Simulate work
+		return n
+	}}
+	c := NewChain[int, int](logger, slow)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	inputChan := make(chan int, 1)
+	inputChan <- 1
+
+	go func() {
+		time.Sleep(10 * time.Millisecond) // Let chain start
+		cancel()
+	}()
+
+	err := c.Run(ctx, inputChan)
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	close(inputChan)
+}
@@ -0,0 +1,89 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrorPolicy controls how a pipeline reacts when one of its stages returns an error.
+type ErrorPolicy int
+
+const (
+	// FailFast cancels all other stages as soon as one stage fails.
+	FailFast ErrorPolicy = iota
+	// ContinueOnError lets the remaining stages run to completion and collects every failure.
+	ContinueOnError
+)
+
+// String returns the command-line spelling of the policy.
+func (p ErrorPolicy) String() string {
+	switch p {
+	case FailFast:
+		return "fail-fast"
+	case ContinueOnError:
+		return "continue"
+	default:
+		return fmt.Sprintf("ErrorPolicy(%d)", int(p))
+	}
+}
+
+// ParseErrorPolicy converts a command-line value ("fail-fast" or "continue") into an ErrorPolicy.
+//
+// Parameters:
+//   - s: The policy name.
+//
+// Returns:
+//   - The matching ErrorPolicy, or an error if the name is unknown.
+func ParseErrorPolicy(s string) (ErrorPolicy, error) {
+	switch s {
+	case "fail-fast":
+		return FailFast, nil
+	case "continue":
+		return ContinueOnError, nil
+	default:
+		return FailFast, fmt.Errorf("unknown error policy %q (expected fail-fast or continue)", s)
+	}
+}
+
+// StageError records the failure of a single stage, identified by its position in the pipeline.
+type StageError struct {
+	Stage int   // Zero-based index of the failed stage
+	Err   error // Error returned by the stage
+}
+
+// Error implements the error interface.
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %d: %v", e.Stage, e.Err)
+}
+
+// Unwrap returns the underlying stage error.
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// joinStageErrors aggregates stage failures ordered by stage index.
+// Cancellation errors are dropped when another stage failed, since they are only a consequence
+// of the fail-fast cancellation.
+func joinStageErrors(errs []*StageError) error {
+	var causes []*StageError
+	for _, err := range errs {
+		if !errors.Is(err.Err, context.Canceled) {
+			causes = append(causes, err)
+		}
+	}
+	if len(causes) == 0 {
+		causes = errs
+	}
+	if len(causes) == 0 {
+		return nil
+	}
+
+	sort.Slice(causes, func(i, j int) bool { return causes[i].Stage < causes[j].Stage })
+	joined := make([]error, len(causes))
+	for i, err := range causes {
+		joined[i] = err
+	}
+	return errors.Join(joined...)
+}
@@ -0,0 +1,61 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseErrorPolicy(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  ErrorPolicy
+		expectErr bool
+	}{
+		{input: "fail-fast", expected: FailFast},
+		{input: "continue", expected: ContinueOnError},
+		{input: "ignore", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			policy, err := ParseErrorPolicy(tt.input)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if policy != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, policy)
+			}
+			if policy.String() != tt.input {
+				t.Errorf("expected String() %q, got %q", tt.input, policy.String())
+			}
+		})
+	}
+}
+
+func TestJoinStageErrors(t *testing.T) {
+	failure := errors.New("boom")
+
+	if err := joinStageErrors(nil); err != nil {
+		t.Errorf("expected nil for no errors, got %v", err)
+	}
+
+	err := joinStageErrors([]*StageError{
+		{Stage: 2, Err: context.Canceled},
+		{Stage: 1, Err: failure},
+	})
+	if err == nil || err.Error() != "stage 1: boom" {
+		t.Errorf("expected only the stage 1 failure, got %v", err)
+	}
+
+	err = joinStageErrors([]*StageError{{Stage: 0, Err: context.Canceled}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation to be kept when it is the only error, got %v", err)
+	}
+}
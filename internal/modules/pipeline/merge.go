@@ -0,0 +1,55 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// mergedSources runs several source stages as one.
+type mergedSources[Out any] struct {
+	sources []Stage[struct{}, Out]
+}
+
+// Merge combines source stages, which generate their own data instead of reading input, into a single
+// source stage that runs them concurrently and sends the output of all of them to its output channel.
+//
+// Parameters:
+//   - sources: The source stages to combine.
+//
+// Returns:
+//   - A Stage running every source until all of them have finished.
+func Merge[Out any](sources ...Stage[struct{}, Out]) Stage[struct{}, Out] {
+	if len(sources) == 1 {
+		return sources[0]
+	}
+	return &mergedSources[Out]{sources: sources}
+}
+
+// Execute runs every source with a closed input channel and waits for all of them to finish.
+// A failing source does not stop the others; the pipeline's error policy decides what happens next.
+func (m *mergedSources[Out]) Execute(ctx context.Context, input <-chan struct{}, output chan<- Out, logger *zap.Logger) error {
+	empty := make(chan struct{})
+	close(empty)
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	wg.Add(len(m.sources))
+	for _, source := range m.sources {
+		go func(source Stage[struct{}, Out]) {
+			defer wg.Done()
+			if err := source.Execute(ctx, empty, output, logger); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(source)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
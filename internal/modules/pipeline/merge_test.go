@@ -0,0 +1,57 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+type sourceStage struct {
+	items []string
+	err   error
+}
+
+func (s *sourceStage) Execute(ctx context.Context, input <-chan struct{}, output chan<- string, logger *zap.Logger) error {
+	for range input {
+	}
+	for _, item := range s.items {
+		output <- item
+	}
+	return s.err
+}
+
+func TestMerge(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	errFailed := errors.New("source failed")
+	sink := &collectStage[string]{}
+	c := Then(NewChain(logger, Merge[string](
+		&sourceStage{items: []string{"a", "b"}},
+		&sourceStage{items: []string{"c"}, err: errFailed},
+		&sourceStage{items: []string{"d"}},
+	)), sink)
+	c.SetErrorPolicy(ContinueOnError)
+
+	input := make(chan struct{})
+	close(input)
+	err := c.Run(context.Background(), input)
+	if !errors.Is(err, errFailed) {
+		t.Errorf("expected the source error, got %v", err)
+	}
+
+	slices.Sort(sink.items)
+	if !slices.Equal(sink.items, []string{"a", "b", "c", "d"}) {
+		t.Errorf("expected the items of every source, got %v", sink.items)
+	}
+}
+
+func TestMerge_Single(t *testing.T) {
+	source := &sourceStage{}
+	if Merge[string](source) != Stage[struct{}, string](source) {
+		t.Error("expected a single source to be returned unwrapped")
+	}
+}
@@ -3,20 +3,34 @@ package pipeline
 import (
 	"context"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 )
 
-// Stage defines the interface for a pipeline stage.
-// Each stage processes input from an input channel and sends results to an output channel.
-type Stage interface {
-	Execute(ctx context.Context, input <-chan interface{}, output chan<- interface{}, logger *zap.Logger) error
+const (
+	bufferSize  = 50              // Capacity of the channels created between stages
+	cancelGrace = 2 * time.Second // How long a canceled run waits for its stages to wind down
+)
+
+// Stage defines the interface for a type-safe pipeline stage.
+// Each stage processes In values from an input channel and sends Out values to an output channel.
+type Stage[In, Out any] interface {
+	Execute(ctx context.Context, input <-chan In, output chan<- Out, logger *zap.Logger) error
 }
 
-// Pipeline manages a sequence of stages that process data in a chain.
+// LegacyStage defines the interface for an untyped pipeline stage exchanging interface{} values.
+// It is kept so that stages written before the typed API keep working; see Adapt and Erase.
+type LegacyStage = Stage[interface{}, interface{}]
+
+// stageFunc runs a single, already wired stage until it finishes.
+type stageFunc func(ctx context.Context) error
+
+// Pipeline manages a sequence of untyped stages that process data in a chain.
 type Pipeline struct {
-	stages []Stage     // List of stages in the pipeline
-	logger *zap.Logger // Logger for pipeline-wide logging
+	stages []LegacyStage // List of stages in the pipeline
+	logger *zap.Logger   // Logger for pipeline-wide logging
+	policy ErrorPolicy   // How stage errors affect the other stages
 }
 
 // New creates a new Pipeline instance with the given logger.
@@ -36,10 +50,18 @@ func New(logger *zap.Logger) *Pipeline {
 //
 // Parameters:
 //   - stage: The stage to add.
-func (p *Pipeline) AddStage(stage Stage) {
+func (p *Pipeline) AddStage(stage LegacyStage) {
 	p.stages = append(p.stages, stage)
 }
 
+// SetErrorPolicy sets how the pipeline reacts to a failing stage. The default is FailFast.
+//
+// Parameters:
+//   - policy: The error policy to apply on the next Run.
+func (p *Pipeline) SetErrorPolicy(policy ErrorPolicy) {
+	p.policy = policy
+}
+
 // Run executes the pipeline with the given input channel.
 //
 // The pipeline chains stages such that each stage's output becomes the next stage's input.
@@ -50,37 +72,73 @@ func (p *Pipeline) AddStage(stage Stage) {
 //   - input: Initial input channel for the first stage.
 //
 // Returns:
-//   - An error if the pipeline fails to complete (e.g., due to cancellation), nil otherwise.
+//   - ctx.Err() if ctx is canceled, once every stage has returned or a grace period has passed, an error
+//     joining one StageError per failed stage if any stage fails, nil otherwise.
 func (p *Pipeline) Run(ctx context.Context, input <-chan interface{}) error {
 	if len(p.stages) == 0 {
 		p.logger.Warn("no stages in pipeline")
 		return nil
 	}
 
-	channels := make([]chan interface{}, len(p.stages))
-	for i := range channels {
-		channels[i] = make(chan interface{}, 50)
+	funcs := make([]stageFunc, len(p.stages))
+	in := input
+	for i, stage := range p.stages {
+		out := make(chan interface{}, bufferSize)
+		funcs[i] = bind(stage, in, out, p.logger, i > 0)
+		in = out
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(len(p.stages))
+	// The last stage's output has no consumer, so discard whatever it emits.
+	go drain(in)
 
-	for i, stage := range p.stages {
-		inChan := input
-		if i > 0 {
-			inChan = channels[i-1]
+	return execute(ctx, p.logger, p.policy, funcs)
+}
+
+// bind wires a stage to its input and output channels.
+// The output channel is closed once the stage returns. When drainInput is set, any input the
+// stage left unread is discarded afterwards so that upstream stages are never blocked forever.
+func bind[In, Out any](stage Stage[In, Out], in <-chan In, out chan<- Out, logger *zap.Logger, drainInput bool) stageFunc {
+	return func(ctx context.Context) error {
+		if drainInput {
+			defer drain(in)
 		}
-		outChan := channels[i]
+		defer close(out)
+		return stage.Execute(ctx, in, out, logger)
+	}
+}
+
+// execute runs the wired stages concurrently and waits for them to finish. When ctx is canceled, it waits up
+// to cancelGrace for the stages to wind down, so that a stage only stopping once its input is closed cannot
+// hang the run.
+//
+// Stage errors are collected as StageError values. Under FailFast the first error cancels the context
+// shared by all stages; under ContinueOnError the remaining stages keep running.
+func execute(ctx context.Context, logger *zap.Logger, policy ErrorPolicy, funcs []stageFunc) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		go func(stage Stage, in <-chan interface{}, out chan<- interface{}, idx int) {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []*StageError
+	)
+	wg.Add(len(funcs))
+
+	for i, fn := range funcs {
+		go func(fn stageFunc, idx int) {
 			defer wg.Done()
-			defer close(out)
-			if err := stage.Execute(ctx, in, out, p.logger); err != nil {
-				p.logger.Error("stage execution failed",
+			if err := fn(runCtx); err != nil {
+				logger.Error("stage execution failed",
 					zap.Int("stage", idx),
 					zap.Error(err))
+				mu.Lock()
+				errs = append(errs, &StageError{Stage: idx, Err: err})
+				mu.Unlock()
+				if policy == FailFast {
+					cancel()
+				}
 			}
-		}(stage, inChan, outChan, i)
+		}(fn, i)
 	}
 
 	done := make(chan struct{})
@@ -91,10 +149,36 @@ func (p *Pipeline) Run(ctx context.Context, input <-chan interface{}) error {
 
 	select {
 	case <-done:
-		p.logger.Info("pipeline completed successfully")
-		return nil
-	case <-ctx.Done():
-		p.logger.Info("pipeline canceled", zap.Error(ctx.Err()))
+	case <-runCtx.Done():
+		if ctx.Err() == nil {
+			// A stage failed under FailFast; let the others observe the cancellation and wind down.
+			<-done
+			break
+		}
+		// Let the stages wind down, so that nothing is still writing once Run returns in the usual case.
+		timer := time.NewTimer(cancelGrace)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+			logger.Warn("stages still running after cancellation", zap.Duration("grace", cancelGrace))
+		}
+		logger.Info("pipeline canceled", zap.Error(ctx.Err()))
 		return ctx.Err()
 	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := joinStageErrors(errs); err != nil {
+		logger.Error("pipeline completed with errors", zap.Error(err))
+		return err
+	}
+	logger.Info("pipeline completed successfully")
+	return nil
+}
+
+// drain discards every value remaining on ch until it is closed.
+func drain[T any](ch <-chan T) {
+	for range ch {
+	}
 }
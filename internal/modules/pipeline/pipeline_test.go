@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -102,3 +103,102 @@ func TestPipeline_Cancel(t *testing.T) {
 	}
 	close(inputChan)
 }
+
+// windDownStage takes a while to finish once ctx is canceled, like a stage flushing its output.
+type windDownStage struct {
+	finished bool
+}
+
+func (w *windDownStage) Execute(ctx context.Context, input <-chan interface{}, output chan<- interface{}, logger *zap.Logger) error {
+	<-ctx.Done()
+	time.Sleep(50 * time.Millisecond)
+	w.finished = true
+	return nil
+}
+
+func TestPipeline_CancelWaitsForStages(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	stage := &windDownStage{}
+	p := New(logger)
+	p.AddStage(stage)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond) // Let pipeline start
+		cancel()
+	}()
+
+	if err := p.Run(ctx, nil); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if !stage.finished {
+		t.Error("expected Run to return after the stage finished")
+	}
+}
+
+// blockingStage forwards its input until ctx is canceled, then reports the cancellation.
+type blockingStage struct{}
+
+func (b *blockingStage) Execute(ctx context.Context, input <-chan interface{}, output chan<- interface{}, logger *zap.Logger) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// failingStage returns err immediately.
+type failingStage struct {
+	err error
+}
+
+func (f *failingStage) Execute(ctx context.Context, input <-chan interface{}, output chan<- interface{}, logger *zap.Logger) error {
+	return f.err
+}
+
+func TestPipeline_FailFast(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	failure := errors.New("csv missing")
+
+	p := New(logger)
+	p.AddStage(&failingStage{err: failure})
+	p.AddStage(&blockingStage{})
+
+	inputChan := make(chan interface{})
+	close(inputChan)
+
+	err := p.Run(context.Background(), inputChan)
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected error wrapping %v, got %v", failure, err)
+	}
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != 0 {
+		t.Errorf("expected StageError for stage 0, got %v", err)
+	}
+	if errors.Is(err, context.Canceled) {
+		t.Errorf("expected the induced cancellation to be omitted, got %v", err)
+	}
+}
+
+func TestPipeline_ContinueOnError(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	failure := errors.New("persist failed")
+
+	p := New(logger)
+	p.SetErrorPolicy(ContinueOnError)
+	p.AddStage(&mockStage{
+		process: func(ctx context.Context, input interface{}) (interface{}, error) {
+			return input, nil
+		},
+	})
+	p.AddStage(&failingStage{err: failure})
+
+	inputChan := make(chan interface{}, 100)
+	for i := 0; i < 100; i++ {
+		inputChan <- i // More than the inter-stage buffer, so the first stage relies on draining
+	}
+	close(inputChan)
+
+	err := p.Run(context.Background(), inputChan)
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != 1 || !errors.Is(err, failure) {
+		t.Errorf("expected StageError for stage 1 wrapping %v, got %v", failure, err)
+	}
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TempPattern is the os.CreateTemp pattern of files being written atomically.
+// Files matching it are never complete and can be removed once no writer is running.
+const TempPattern = ".urldownloader-*.tmp"
+
+// IsTemp reports whether a file name matches TempPattern.
+func IsTemp(name string) bool {
+	prefix, suffix, _ := strings.Cut(TempPattern, "*")
+	return strings.HasPrefix(name, prefix) && strings.HasSuffix(name, suffix) && len(name) > len(prefix)+len(suffix)
+}
+
+// WriteFileAtomic writes the data read from r to path so that readers see either the previous file or the
+// complete new one: the data goes to a temporary file in the same directory, which is synced to disk and then
+// renamed over path. Parent directories are created as needed.
+//
+// Parameters:
+//   - path: Destination file path.
+//   - r: The data to write.
+//   - perm: Permissions of the new file.
+//
+// Returns:
+//   - An error if writing fails, in which case path is left untouched.
+func WriteFileAtomic(path string, r io.Reader, perm fs.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, TempPattern)
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	committed = true
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes a directory so that a rename inside it survives a crash.
+// Failures are ignored, since not every platform supports syncing directories.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+// RemoveTemp removes the temporary files left below root by writers that were interrupted,
+// e.g. by a crash or SIGKILL. It must not run while another process writes below root.
+//
+// Parameters:
+//   - root: The directory to clean.
+//
+// Returns:
+//   - The number of files removed, and an error if root cannot be walked.
+func RemoveTemp(root string) (int, error) {
+	removed := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == root {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !IsTemp(d.Name()) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failingReader yields some data and then fails, like a connection dropped mid-download.
+type failingReader struct {
+	r io.Reader
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "app.jar")
+
+	if err := WriteFileAtomic(path, strings.NewReader("complete"), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0644 {
+		t.Fatalf("expected file with mode 0644, got %v, %v", info, err)
+	}
+
+	// A failed write leaves the previous file untouched and no temporary file behind.
+	if err := WriteFileAtomic(path, &failingReader{strings.NewReader("trunc")}, 0644); err == nil {
+		t.Fatal("expected error from failing reader")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "complete" {
+		t.Errorf("expected previous content, got %q", data)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected only the target file, got %v", entries)
+	}
+}
+
+func TestRemoveTemp(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]bool{ // file name -> whether it is a leftover temporary file
+		"app.jar":                          false,
+		"example.com/.urldownloader-1.tmp": true,
+		".urldownloader-2.tmp":             true,
+		"example.com/.urldownloader-.tmp":  false,
+		"notes.tmp":                        false,
+	}
+	for name := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := RemoveTemp(root)
+	if err != nil || removed != 2 {
+		t.Fatalf("expected 2 files removed, got %d, %v", removed, err)
+	}
+	for name, temp := range files {
+		_, err := os.Stat(filepath.Join(root, filepath.FromSlash(name)))
+		if exists := err == nil; exists == temp {
+			t.Errorf("%s: expected exists=%v", name, !temp)
+		}
+	}
+
+	if removed, err := RemoveTemp(filepath.Join(root, "missing")); err != nil || removed != 0 {
+		t.Errorf("expected missing root to be ignored, got %d, %v", removed, err)
+	}
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// HTTP stores objects in a generic repository that accepts uploads with PUT, such as an Artifactory generic
+// repository or a WebDAV share. Credentials in the base URL are sent with HTTP basic authentication.
+type HTTP struct {
+	base     *url.URL     // Repository URL the keys are resolved against, without credentials
+	username string       // Basic authentication user, if any
+	password string       // Basic authentication password, if any
+	client   *http.Client // Client used for all requests
+}
+
+var _ Storage = (*HTTP)(nil)
+
+// NewHTTP creates an HTTP storage for the repository at base.
+//
+// Parameters:
+//   - base: The repository URL; may carry user:password credentials.
+//   - client: The HTTP client to use; http.DefaultClient if nil.
+//
+// Returns:
+//   - A pointer to a new HTTP instance.
+func NewHTTP(base *url.URL, client *http.Client) *HTTP {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	h := &HTTP{client: client}
+
+	u := *base
+	if u.User != nil {
+		h.username = u.User.Username()
+		h.password, _ = u.User.Password()
+		u.User = nil
+	}
+	h.base = &u
+	return h
+}
+
+// Put uploads the data read from r to the URL of key.
+func (h *HTTP) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	body, size, err := requestBody(r, size)
+	if err != nil {
+		return err
+	}
+	resp, err := h.do(ctx, http.MethodPut, key, body, size)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp, key, http.StatusOK, http.StatusCreated, http.StatusNoContent)
+}
+
+// Get downloads the object at the URL of key.
+func (h *HTTP) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := h.do(ctx, http.MethodGet, key, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp, key, http.StatusOK); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Stat describes the object at the URL of key using a HEAD request.
+func (h *HTTP) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := h.do(ctx, http.MethodHead, key, nil, 0)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer resp.Body.Close()
+	if err := checkStatus(resp, key, http.StatusOK); err != nil {
+		return ObjectInfo{}, err
+	}
+	return objectInfo(key, resp.Header), nil
+}
+
+// Delete removes the object at the URL of key.
+func (h *HTTP) Delete(ctx context.Context, key string) error {
+	resp, err := h.do(ctx, http.MethodDelete, key, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp, key, http.StatusOK, http.StatusAccepted, http.StatusNoContent)
+}
+
+// List is not supported, since generic repositories have no common listing API.
+//
+// Returns:
+//   - An error wrapping errors.ErrUnsupported.
+func (h *HTTP) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	return nil, fmt.Errorf("listing a generic HTTP repository: %w", errors.ErrUnsupported)
+}
+
+// do sends a request for key.
+func (h *HTTP) do(ctx context.Context, method, key string, body io.Reader, size int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, h.base.JoinPath(strings.Split(key, "/")...).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	if h.username != "" || h.password != "" {
+		req.SetBasicAuth(h.username, h.password)
+	}
+	return h.client.Do(req)
+}
+
+// objectInfo describes the object of key from the headers of a GET or HEAD response.
+func objectInfo(key string, header http.Header) ObjectInfo {
+	info := ObjectInfo{Key: key, Size: -1}
+	if size, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil {
+		info.Size = size
+	}
+	if modTime, err := http.ParseTime(header.Get("Last-Modified")); err == nil {
+		info.ModTime = modTime
+	}
+	return info
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHTTP(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		objects = make(map[string][]byte)
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "deployer" || pass != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = data
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet, http.MethodHead:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		case http.MethodDelete:
+			if _, ok := objects[r.URL.Path]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	base, _ := url.Parse(ts.URL + "/artifactory/generic-local/")
+	base.User = url.UserPassword("deployer", "s3cret")
+	h := NewHTTP(base, ts.Client())
+	ctx := context.Background()
+
+	if err := h.Put(ctx, "example.com/app 1.0.jar", strings.NewReader("jar"), 3); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if _, ok := objects["/artifactory/generic-local/example.com/app 1.0.jar"]; !ok {
+		t.Errorf("expected object below the base path, got %v", objects)
+	}
+
+	body, err := h.Get(ctx, "example.com/app 1.0.jar")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "jar" {
+		t.Errorf("expected jar, got %q", data)
+	}
+
+	if info, err := h.Stat(ctx, "example.com/app 1.0.jar"); err != nil || info.Size != 3 {
+		t.Errorf("unexpected stat %+v, %v", info, err)
+	}
+	if err := h.Delete(ctx, "example.com/app 1.0.jar"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := h.Stat(ctx, "example.com/app 1.0.jar"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if _, err := h.List(ctx, ""); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported from List, got %v", err)
+	}
+
+	unauthorized := NewHTTP(&url.URL{Scheme: "http", Host: base.Host, Path: base.Path}, ts.Client())
+	if err := unauthorized.Put(ctx, "key", strings.NewReader("x"), 1); err == nil {
+		t.Error("expected error without credentials")
+	}
+}
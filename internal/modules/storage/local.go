@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores objects as files below a root directory.
+type Local struct {
+	root string // Directory holding the objects
+}
+
+var _ Storage = (*Local)(nil)
+
+// NewLocal creates a Local storage rooted at the given directory, which is created on the first Put.
+//
+// Parameters:
+//   - root: The directory path.
+//
+// Returns:
+//   - A pointer to a new Local instance.
+func NewLocal(root string) *Local {
+	return &Local{root: root}
+}
+
+// Root returns the directory the objects are stored in.
+func (l *Local) Root() string {
+	return l.root
+}
+
+// Path returns the file an object is stored in.
+//
+// Returns:
+//   - The file path, or an error if key is absolute or escapes the root directory.
+func (l *Local) Path(key string) (string, error) {
+	rel := filepath.FromSlash(key)
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("key %q escapes the storage root", key)
+	}
+	return filepath.Join(l.root, rel), nil
+}
+
+// Put writes the data read from r to the file of key atomically (see WriteFileAtomic),
+// so an interrupted write never leaves a truncated file behind.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	path, err := l.Path(key)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, r, 0644)
+}
+
+// RemoveTemp removes temporary files left below the root by interrupted writes; see RemoveTemp.
+func (l *Local) RemoveTemp() (int, error) {
+	return RemoveTemp(l.root)
+}
+
+// Get opens the file of key.
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.Path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, notFound(key)
+	}
+	return file, err
+}
+
+// Stat describes the file of key.
+func (l *Local) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	path, err := l.Path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ObjectInfo{}, notFound(key)
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Key: key, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// Delete removes the file of key.
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.Path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return notFound(key)
+	}
+	return err
+}
+
+// List describes every regular file below the root whose key starts with prefix, in lexical order.
+// Temporary files of writes in progress are skipped.
+func (l *Local) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(l.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == l.root {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() || IsTemp(d.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	return objects, err
+}
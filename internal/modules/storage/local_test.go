@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "downloads")
+	l := NewLocal(root)
+	ctx := context.Background()
+
+	// Listing a root that does not exist yet is not an error.
+	if objects, err := l.List(ctx, ""); err != nil || len(objects) != 0 {
+		t.Errorf("expected empty listing, got %v, %v", objects, err)
+	}
+
+	for key, data := range map[string]string{"a.txt": "a", "example.com/b.jar": "bb", "example.com/c.jar": "ccc"} {
+		if err := l.Put(ctx, key, strings.NewReader(data), -1); err != nil {
+			t.Fatalf("put %s failed: %v", key, err)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "example.com", "b.jar")); err != nil || string(data) != "bb" {
+		t.Errorf("expected file below the root, got %q, %v", data, err)
+	}
+
+	body, err := l.Get(ctx, "example.com/c.jar")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "ccc" {
+		t.Errorf("expected ccc, got %q", data)
+	}
+
+	objects, err := l.List(ctx, "example.com/")
+	if err != nil || len(objects) != 2 || objects[0].Key != "example.com/b.jar" || objects[1].Size != 3 {
+		t.Errorf("unexpected listing %+v, %v", objects, err)
+	}
+
+	if err := l.Delete(ctx, "a.txt"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := l.Stat(ctx, "a.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := l.Delete(ctx, "a.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting a missing key, got %v", err)
+	}
+	if err := l.Put(ctx, "../outside.txt", strings.NewReader("x"), 1); err == nil {
+		t.Error("expected error for key escaping the root")
+	}
+}
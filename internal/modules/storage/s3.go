@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// S3Config locates an S3-compatible bucket and holds the credentials to access it.
+type S3Config struct {
+	Endpoint        string // Service URL, e.g. "https://s3.us-east-1.amazonaws.com" or "http://localhost:9000"
+	Region          string // Signing region
+	Bucket          string // Bucket name
+	Prefix          string // Key prefix inside the bucket, without leading or trailing slashes
+	AccessKeyID     string // Access key ID; requests are sent unsigned if empty
+	SecretAccessKey string // Secret access key
+	SessionToken    string // Session token of temporary credentials, if any
+}
+
+// S3ConfigFromURL builds an S3Config from a URL of the form s3://bucket/prefix?endpoint=...&region=...
+// Credentials come from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+// Without endpoint and region parameters, AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL and AWS_REGION or
+// AWS_DEFAULT_REGION are used, falling back to AWS in us-east-1.
+//
+// Parameters:
+//   - u: The s3:// URL.
+//
+// Returns:
+//   - The configuration, or an error if the bucket is missing or the endpoint is not an http(s) URL.
+func S3ConfigFromURL(u *url.URL) (S3Config, error) {
+	query := u.Query()
+	cfg := S3Config{
+		Endpoint:        firstNonEmpty(query.Get("endpoint"), os.Getenv("AWS_ENDPOINT_URL_S3"), os.Getenv("AWS_ENDPOINT_URL")),
+		Region:          firstNonEmpty(query.Get("region"), os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1"),
+		Bucket:          u.Host,
+		Prefix:          strings.Trim(u.Path, "/"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if cfg.Bucket == "" {
+		return cfg, fmt.Errorf("missing bucket in %q", u.Redacted())
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	if endpoint, err := url.Parse(cfg.Endpoint); err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return cfg, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+	}
+	return cfg, nil
+}
+
+// S3 stores objects in an S3-compatible bucket, addressed path-style so that it also works with
+// self-hosted services such as MinIO. Requests are signed with Signature Version 4; uploads are
+// streamed with an unsigned payload.
+type S3 struct {
+	cfg      S3Config         // Bucket location and credentials
+	endpoint *url.URL         // Parsed cfg.Endpoint
+	client   *http.Client     // Client used for all requests
+	now      func() time.Time // Clock used for signing
+}
+
+var _ Storage = (*S3)(nil)
+
+// NewS3 creates an S3 storage for the configured bucket.
+//
+// Parameters:
+//   - cfg: The bucket location and credentials; cfg.Endpoint must be a valid URL (see S3ConfigFromURL).
+//   - client: The HTTP client to use; http.DefaultClient if nil.
+//
+// Returns:
+//   - A pointer to a new S3 instance.
+func NewS3(cfg S3Config, client *http.Client) *S3 {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	endpoint, _ := url.Parse(cfg.Endpoint)
+	return &S3{cfg: cfg, endpoint: endpoint, client: client, now: time.Now}
+}
+
+// Put uploads the data read from r as the object of key.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	body, size, err := requestBody(r, size)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodPut, s.objectKey(key), nil, body, size)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp, key, http.StatusOK)
+}
+
+// Get downloads the object of key.
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.objectKey(key), nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp, key, http.StatusOK); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Stat describes the object of key using a HEAD request.
+func (s *S3) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, s.objectKey(key), nil, nil, 0)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer resp.Body.Close()
+	if err := checkStatus(resp, key, http.StatusOK); err != nil {
+		return ObjectInfo{}, err
+	}
+	return objectInfo(key, resp.Header), nil
+}
+
+// Delete removes the object of key. S3 reports success for keys that do not exist.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.objectKey(key), nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp, key, http.StatusOK, http.StatusNoContent)
+}
+
+// listBucketResult is the response of a ListObjectsV2 request.
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List describes every object whose key starts with prefix, following ListObjectsV2 pagination.
+func (s *S3) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	query := url.Values{"list-type": {"2"}, "prefix": {s.objectKey(prefix)}}
+	trim := ""
+	if s.cfg.Prefix != "" {
+		trim = s.cfg.Prefix + "/"
+	}
+
+	var objects []ObjectInfo
+	for {
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		var result listBucketResult
+		if err = checkStatus(resp, prefix, http.StatusOK); err == nil {
+			err = xml.NewDecoder(resp.Body).Decode(&result)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("list objects: %w", err)
+		}
+
+		for _, c := range result.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:     strings.TrimPrefix(c.Key, trim),
+				Size:    c.Size,
+				ModTime: c.LastModified,
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// objectKey returns the bucket key of key, inside the configured prefix.
+func (s *S3) objectKey(key string) string {
+	if s.cfg.Prefix == "" {
+		return key
+	}
+	return s.cfg.Prefix + "/" + key
+}
+
+// do sends a signed request for an object of the bucket, or for the bucket itself if objectKey is empty.
+func (s *S3) do(ctx context.Context, method, objectKey string, query url.Values, body io.Reader, size int64) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket
+	if objectKey != "" {
+		u.Path += "/" + objectKey
+	}
+	u.RawPath = escapePath(u.Path)
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	if s.cfg.AccessKeyID != "" {
+		creds := credentials{
+			AccessKeyID:     s.cfg.AccessKeyID,
+			SecretAccessKey: s.cfg.SecretAccessKey,
+			SessionToken:    s.cfg.SessionToken,
+		}
+		signV4(req, creds, s.cfg.Region, "s3", unsignedPayload, s.now())
+	}
+	return s.client.Do(req)
+}
+
+// firstNonEmpty returns the first of values that is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is a minimal stand-in for an S3-compatible service with path-style addressing.
+// It rejects requests whose signature differs from the one it computes for a copy of the request.
+type fakeS3 struct {
+	creds   credentials
+	mu      sync.Mutex
+	objects map[string][]byte // Objects keyed by "bucket/key"
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !f.verify(r) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		f.list(w, bucket, r.URL.Query().Get("prefix"), r.URL.Query().Get("continuation-token"))
+	case r.Method == http.MethodPut:
+		if r.Header.Get("Transfer-Encoding") != "" || r.ContentLength < 0 {
+			w.WriteHeader(http.StatusLengthRequired)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		f.objects[bucket+"/"+key] = data
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := f.objects[bucket+"/"+key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Last-Modified", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).Format(http.TimeFormat))
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+	case r.Method == http.MethodDelete:
+		delete(f.objects, bucket+"/"+key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// list answers a ListObjectsV2 request, one object per page.
+func (f *fakeS3) list(w http.ResponseWriter, bucket, prefix, token string) {
+	var keys []string
+	for name := range f.objects {
+		if key := strings.TrimPrefix(name, bucket+"/"); key != name && strings.HasPrefix(key, prefix) && key > token {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var result struct {
+		XMLName  xml.Name `xml:"ListBucketResult"`
+		Contents []struct {
+			Key          string
+			Size         int
+			LastModified string
+		}
+		IsTruncated           bool
+		NextContinuationToken string `xml:",omitempty"`
+	}
+	if len(keys) > 0 {
+		result.Contents = append(result.Contents, struct {
+			Key          string
+			Size         int
+			LastModified string
+		}{keys[0], len(f.objects[bucket+"/"+keys[0]]), "2024-01-02T03:04:05.000Z"})
+		result.IsTruncated = len(keys) > 1
+		if result.IsTruncated {
+			result.NextContinuationToken = keys[0]
+		}
+	}
+	xml.NewEncoder(w).Encode(result)
+}
+
+// verify recomputes the signature of r and compares it with the one it carries.
+func (f *fakeS3) verify(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if r.Header.Get("X-Amz-Content-Sha256") != unsignedPayload {
+		return false
+	}
+	date, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+	_, signed, _ := strings.Cut(auth, "SignedHeaders=")
+	signed, _, _ = strings.Cut(signed, ",")
+
+	u := *r.URL
+	u.Scheme, u.Host = "http", r.Host
+	clone, _ := http.NewRequest(r.Method, u.String(), nil)
+	for _, name := range strings.Split(signed, ";") {
+		switch name {
+		case "host", "x-amz-date", "x-amz-content-sha256", "x-amz-security-token":
+		default:
+			clone.Header[http.CanonicalHeaderKey(name)] = r.Header.Values(name)
+		}
+	}
+	signV4(clone, f.creds, "eu-west-1", "s3", unsignedPayload, date)
+	return clone.Header.Get("Authorization") == auth
+}
+
+func TestS3(t *testing.T) {
+	creds := credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret", SessionToken: "token"}
+	fake := &fakeS3{creds: creds, objects: make(map[string][]byte)}
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	s := NewS3(S3Config{
+		Endpoint:        ts.URL,
+		Region:          "eu-west-1",
+		Bucket:          "artifacts",
+		Prefix:          "nightly",
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+	}, ts.Client())
+	ctx := context.Background()
+
+	// Keys with characters that need escaping, a streamed upload of unknown size, and an empty object.
+	uploads := map[string]string{
+		"example.com/app 1.0+build.jar": "jar",
+		"example.com/notes.txt":         "streamed notes",
+		"example.com/empty":             "",
+	}
+	for key, data := range uploads {
+		size := int64(len(data))
+		if key == "example.com/notes.txt" {
+			size = -1
+		}
+		if err := s.Put(ctx, key, strings.NewReader(data), size); err != nil {
+			t.Fatalf("put %s failed: %v", key, err)
+		}
+	}
+	if _, ok := fake.objects["artifacts/nightly/example.com/app 1.0+build.jar"]; !ok {
+		t.Errorf("expected object under the prefix, got %v", fake.objects)
+	}
+
+	body, err := s.Get(ctx, "example.com/app 1.0+build.jar")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "jar" {
+		t.Errorf("expected jar, got %q", data)
+	}
+
+	info, err := s.Stat(ctx, "example.com/notes.txt")
+	if err != nil || info.Size != int64(len("streamed notes")) || info.ModTime.IsZero() {
+		t.Errorf("unexpected stat %+v, %v", info, err)
+	}
+
+	objects, err := s.List(ctx, "example.com/")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var keys []string
+	for _, o := range objects {
+		keys = append(keys, o.Key)
+	}
+	if strings.Join(keys, ",") != "example.com/app 1.0+build.jar,example.com/empty,example.com/notes.txt" {
+		t.Errorf("unexpected listing %v", keys)
+	}
+
+	if err := s.Delete(ctx, "example.com/notes.txt"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := s.Stat(ctx, "example.com/notes.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for missing key, got %v", err)
+	}
+}
+
+func TestS3_BadCredentials(t *testing.T) {
+	fake := &fakeS3{creds: credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}, objects: make(map[string][]byte)}
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	s := NewS3(S3Config{Endpoint: ts.URL, Region: "eu-west-1", Bucket: "b", AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wrong"}, ts.Client())
+	err := s.Put(context.Background(), "key", strings.NewReader("data"), 4)
+	if err == nil || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("expected signature error, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotFound reports that no object is stored under a key.
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key     string    // Slash-separated key of the object
+	Size    int64     // Size in bytes
+	ModTime time.Time // Time the object was last written, if known
+}
+
+// Storage is a place persisted payloads are written to, addressed by slash-separated keys such as "example.com/app.jar".
+type Storage interface {
+	// Put stores the data read from r under key, replacing any existing object.
+	// size is the number of bytes r yields, or -1 if unknown.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get opens the object stored under key. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Stat describes the object stored under key.
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Delete removes the object stored under key.
+	Delete(ctx context.Context, key string) error
+	// List describes every object whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// Open returns the storage identified by uri:
+//   - a plain path or file://path for a local directory,
+//   - s3://bucket/prefix for an S3-compatible bucket (see S3ConfigFromURL),
+//   - http:// or https:// for a generic repository accepting PUT requests.
+//
+// Parameters:
+//   - uri: The storage location.
+//   - client: The HTTP client remote storages send requests with, which should have timeouts so that an
+//     unresponsive endpoint cannot block forever; http.DefaultClient if nil.
+//
+// Returns:
+//   - The storage, or an error if the scheme is unsupported or the location is incomplete.
+func Open(uri string, client *http.Client) (Storage, error) {
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return NewLocal(uri), nil
+	}
+
+	switch strings.ToLower(scheme) {
+	case "file":
+		if rest == "" {
+			return nil, fmt.Errorf("missing path in %q", uri)
+		}
+		return NewLocal(rest), nil
+	case "s3":
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := S3ConfigFromURL(u)
+		if err != nil {
+			return nil, err
+		}
+		return NewS3(cfg, client), nil
+	case "http", "https":
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, err
+		}
+		return NewHTTP(u, client), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage scheme %q (expected file, s3, http or https)", scheme)
+	}
+}
+
+// notFound wraps ErrNotFound with the missing key.
+func notFound(key string) error {
+	return fmt.Errorf("%w: %s", ErrNotFound, key)
+}
+
+// checkStatus maps the status of a response about key to an error.
+//
+// Parameters:
+//   - resp: The response; its body is read for error details when the status is unexpected.
+//   - key: The key the request was about.
+//   - expected: Statuses that indicate success.
+//
+// Returns:
+//   - nil for an expected status, an error wrapping ErrNotFound for 404, or an error describing the status otherwise.
+func checkStatus(resp *http.Response, key string, expected ...int) error {
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return notFound(key)
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if msg := strings.TrimSpace(string(detail)); msg != "" {
+		return fmt.Errorf("bad status: %d: %s", resp.StatusCode, msg)
+	}
+	return fmt.Errorf("bad status: %d", resp.StatusCode)
+}
+
+// requestBody returns a request body for r, buffering it in memory if its size is unknown,
+// since not every server accepts chunked uploads.
+//
+// Returns:
+//   - The body and its size, or an error if r cannot be read.
+func requestBody(r io.Reader, size int64) (io.Reader, int64, error) {
+	if size < 0 {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, 0, err
+		}
+		r, size = bytes.NewReader(data), int64(len(data))
+	}
+	if size == 0 {
+		// A zero ContentLength with a non-nil body would be sent chunked.
+		return http.NoBody, 0, nil
+	}
+	return r, size, nil
+}
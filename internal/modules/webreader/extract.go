@@ -0,0 +1,204 @@
+package webreader
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Link is a link found on an HTML page or in a stylesheet.
+type Link struct {
+	URL  string // Absolute http or https URL of the target, without fragment
+	Line int    // Line of the document the link starts on
+}
+
+// linkAttrs names the attribute holding the link target of each tag ExtractLinks follows.
+var linkAttrs = map[string]string{
+	"a":    "href",
+	"area": "href",
+}
+
+// referenceAttrs names the attribute holding the target of each tag ExtractReferences follows.
+var referenceAttrs = map[string]string{
+	"a":      "href",
+	"area":   "href",
+	"link":   "href",
+	"img":    "src",
+	"script": "src",
+	"iframe": "src",
+	"frame":  "src",
+	"embed":  "src",
+	"source": "src",
+	"audio":  "src",
+	"video":  "src",
+	"track":  "src",
+}
+
+// cssURL matches url(...) references and @import rules in a stylesheet; the target is in one of the groups.
+var cssURL = regexp.MustCompile(`url\(\s*(?:"([^"]*)"|'([^']*)'|([^'")\s]*))\s*\)|@import\s+(?:"([^"]*)"|'([^']*)')`)
+
+// ExtractLinks returns the http and https targets of the <a> and <area> links on an HTML page, in order,
+// resolved against the page URL or its <base href>. Links to the page itself are omitted.
+//
+// Parameters:
+//   - page: The HTML document.
+//   - pageURL: The URL the document was fetched from.
+//
+// Returns:
+//   - The links, or an error if pageURL is invalid or the document cannot be tokenized.
+func ExtractLinks(page []byte, pageURL string) ([]Link, error) {
+	return extractHTML(page, pageURL, linkAttrs, false)
+}
+
+// ExtractReferences returns every http and https URL an HTML page refers to, in order: the targets of
+// links, stylesheets, images, scripts, frames and media sources, and the url() references of <style>
+// elements and style attributes. URLs are resolved like those of ExtractLinks.
+//
+// Parameters:
+//   - page: The HTML document.
+//   - pageURL: The URL the document was fetched from.
+//
+// Returns:
+//   - The references, or an error if pageURL is invalid or the document cannot be tokenized.
+func ExtractReferences(page []byte, pageURL string) ([]Link, error) {
+	return extractHTML(page, pageURL, referenceAttrs, true)
+}
+
+// ExtractCSSLinks returns the http and https targets of the url() references and @import rules of a
+// stylesheet, in order, resolved against the stylesheet URL. Non-http references such as data: URLs are omitted.
+//
+// Parameters:
+//   - css: The stylesheet.
+//   - styleURL: The URL the stylesheet was fetched from.
+//
+// Returns:
+//   - The references, or an error if cssURL is invalid.
+func ExtractCSSLinks(css []byte, styleURL string) ([]Link, error) {
+	base, err := url.Parse(styleURL)
+	if err != nil {
+		return nil, err
+	}
+	return cssLinks(css, base, 1, nil), nil
+}
+
+// extractHTML tokenizes an HTML page and collects the targets of the given tag attributes and,
+// if withCSS is set, of the url() references in its styles.
+func extractHTML(page []byte, pageURL string, attrs map[string]string, withCSS bool) ([]Link, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	self := *base
+	self.Fragment, self.RawFragment = "", ""
+
+	var (
+		links   []Link
+		line    = 1
+		hasBase bool
+		inStyle bool
+	)
+	z := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		tokenType := z.Next()
+		start := line
+		line += bytes.Count(z.Raw(), []byte{'\n'})
+
+		switch tokenType {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return omit(links, self.String()), nil
+			}
+			return nil, z.Err()
+		case html.TextToken:
+			if inStyle {
+				links = cssLinks(z.Text(), base, start, links)
+			}
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "style" {
+				inStyle = false
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			tag := string(name)
+			if tag == "style" && withCSS && tokenType == html.StartTagToken {
+				inStyle = true
+			}
+			if !hasAttr {
+				continue
+			}
+			values := attributes(z)
+			if withCSS && values["style"] != "" {
+				links = cssLinks([]byte(values["style"]), base, start, links)
+			}
+			if tag == "base" {
+				if ref, err := base.Parse(values["href"]); err == nil && !hasBase && values["href"] != "" {
+					base, hasBase = ref, true
+				}
+				continue
+			}
+			if attr, ok := attrs[tag]; ok && values[attr] != "" {
+				links = appendLink(links, base, values[attr], start)
+			}
+		}
+	}
+}
+
+// cssLinks appends the url() and @import targets of a stylesheet starting on the given line to links.
+func cssLinks(css []byte, base *url.URL, line int, links []Link) []Link {
+	offset := 0
+	for _, match := range cssURL.FindAllSubmatchIndex(css, -1) {
+		line += bytes.Count(css[offset:match[0]], []byte{'\n'})
+		offset = match[0]
+		for group := 1; group < len(match)/2; group++ {
+			if match[2*group] >= 0 {
+				links = appendLink(links, base, string(css[match[2*group]:match[2*group+1]]), line)
+				break
+			}
+		}
+	}
+	return links
+}
+
+// appendLink resolves ref against base and appends it to links if it is an http or https URL.
+func appendLink(links []Link, base *url.URL, ref string, line int) []Link {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return links
+	}
+	target, err := base.Parse(ref)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		return links
+	}
+	target.Fragment, target.RawFragment = "", ""
+	return append(links, Link{URL: target.String(), Line: line})
+}
+
+// omit removes the links to the given URL.
+func omit(links []Link, url string) []Link {
+	kept := links[:0]
+	for _, link := range links {
+		if link.URL != url {
+			kept = append(kept, link)
+		}
+	}
+	return kept
+}
+
+// attributes returns the trimmed attributes of the current tag by lowercased name.
+func attributes(z *html.Tokenizer) map[string]string {
+	values := make(map[string]string)
+	for {
+		key, value, more := z.TagAttr()
+		if _, ok := values[string(key)]; !ok {
+			values[string(key)] = strings.TrimSpace(string(value))
+		}
+		if !more {
+			return values
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package webreader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	page := `<!DOCTYPE html>
+<html><head><title>Index of /repo</title></head>
+<body>
+<a href="?C=N;O=D">Name</a>
+<a href="../">Parent Directory</a>
+<a href="app-1.0.jar">app-1.0.jar</a>
+<a
+  href="sub/app-1.0.pom#top">pom</a>
+<a href="mailto:admin@example.com">mail</a>
+<a href="https://cdn.example.com/x.zip">x.zip</a>
+<a name="anchor">no href</a>
+<a href="#section">section</a>
+<map><area href="/map.png"></map>
+</body></html>`
+
+	links, err := ExtractLinks([]byte(page), "http://example.com/repo/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Link{
+		{URL: "http://example.com/repo/?C=N;O=D", Line: 4},
+		{URL: "http://example.com/", Line: 5},
+		{URL: "http://example.com/repo/app-1.0.jar", Line: 6},
+		{URL: "http://example.com/repo/sub/app-1.0.pom", Line: 7},
+		{URL: "https://cdn.example.com/x.zip", Line: 10},
+		{URL: "http://example.com/map.png", Line: 13},
+	}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("expected %+v, got %+v", expected, links)
+	}
+}
+
+func TestExtractLinks_Base(t *testing.T) {
+	page := `<head><base href="https://mirror.example.com/files/"><base href="/ignored/"></head><a href="a.tar.gz">a</a>`
+	links, err := ExtractLinks([]byte(page), "http://example.com/index.html")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(links) != 1 || links[0].URL != "https://mirror.example.com/files/a.tar.gz" {
+		t.Errorf("expected the link resolved against <base>, got %+v", links)
+	}
+}
+
+func TestExtractReferences(t *testing.T) {
+	page := `<html><head>
+<link rel="stylesheet" href="/css/site.css">
+<style>
+  body { background: url("img/bg.png"); }
+  @import 'print.css';
+</style>
+<script src="https://cdn.example.com/app.js"></script>
+</head>
+<body style="background-image: url(data:image/png;base64,AAAA)">
+<img src="logo.svg" alt="logo"><a href="docs/">Docs</a>
+<div style="background: url( 'hero.jpg' )"></div>
+<video><source src="intro.mp4"></video>
+</body></html>`
+
+	links, err := ExtractReferences([]byte(page), "https://example.com/site/index.html")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Link{
+		{URL: "https://example.com/css/site.css", Line: 2},
+		{URL: "https://example.com/site/img/bg.png", Line: 4},
+		{URL: "https://example.com/site/print.css", Line: 5},
+		{URL: "https://cdn.example.com/app.js", Line: 7},
+		{URL: "https://example.com/site/logo.svg", Line: 10},
+		{URL: "https://example.com/site/docs/", Line: 10},
+		{URL: "https://example.com/site/hero.jpg", Line: 11},
+		{URL: "https://example.com/site/intro.mp4", Line: 12},
+	}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("expected %+v, got %+v", expected, links)
+	}
+}
+
+func TestExtractCSSLinks(t *testing.T) {
+	css := "@import url(\"base.css\");\n@import \"theme.css\";\n.logo {\n  background: url(../img/logo.png) no-repeat, url('data:image/gif;base64,R0lGOD');\n}\n@font-face { src: url(https://fonts.example.com/a.woff2#iefix); }\n"
+
+	links, err := ExtractCSSLinks([]byte(css), "https://example.com/static/css/site.css")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Link{
+		{URL: "https://example.com/static/css/base.css", Line: 1},
+		{URL: "https://example.com/static/css/theme.css", Line: 2},
+		{URL: "https://example.com/static/img/logo.png", Line: 4},
+		{URL: "https://fonts.example.com/a.woff2", Line: 6},
+	}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("expected %+v, got %+v", expected, links)
+	}
+}
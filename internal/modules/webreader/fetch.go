@@ -0,0 +1,116 @@
+package webreader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"jfrog-assignment/internal/models"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// DefaultMaxDocumentSize is the largest document read, after decompression, unless WithMaxDocumentSize is
+// given. It is the limit the sitemap protocol sets for a single sitemap.
+const DefaultMaxDocumentSize = 50 << 20
+
+var gzipMagic = []byte{0x1F, 0x8B} // First bytes of a gzip stream
+
+// Doer sends HTTP requests. Both *http.Client and *downloader.HTTPDownloader, which adds the configured
+// headers and credentials, are Doers.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// config holds the settings shared by the readers of this package.
+type config struct {
+	client  Doer   // Client documents are fetched with
+	filter  Filter // Filter applied to every URL found
+	maxSize int64  // Largest document read, after decompression
+}
+
+// newConfig applies opts to the default settings.
+func newConfig(opts []Option) config {
+	c := config{client: http.DefaultClient, maxSize: DefaultMaxDocumentSize}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// fetch downloads a document, decompressing it if it is gzipped, whatever its Content-Encoding.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - url: The document URL.
+//
+// Returns:
+//   - The document, or an error if the request fails, the response is not 2xx, or the document is too large.
+func (c *config) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &models.StatusError{Code: resp.StatusCode}
+	}
+
+	data, err := c.readLimited(resp.Body)
+	if err != nil || !bytes.HasPrefix(data, gzipMagic) {
+		return data, err
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+	defer gz.Close()
+	return c.readLimited(gz)
+}
+
+// readLimited reads r to the end, failing once more than the maximum document size has been read.
+func (c *config) readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, c.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > c.maxSize {
+		return nil, fmt.Errorf("%w: document larger than %d bytes", models.ErrSizeLimit, c.maxSize)
+	}
+	return data, nil
+}
+
+// recordSink filters the URLs found in documents and sends the matching ones to the pipeline.
+type recordSink struct {
+	ctx      context.Context
+	output   chan<- models.URLRecord
+	logger   *zap.Logger
+	filter   Filter
+	urls     int // Records sent
+	filtered int // URLs rejected by the filter
+}
+
+// emit sends a record for url, found on the given line of the document at source, unless the filter rejects it.
+//
+// Returns:
+//   - ctx.Err() if reading was canceled, nil otherwise.
+func (s *recordSink) emit(url, source string, line int) error {
+	if !s.filter.Match(url) {
+		s.logger.Debug("URL filtered out", zap.String("url", url))
+		s.filtered++
+		return nil
+	}
+	select {
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	case s.output <- models.URLRecord{URL: url, Source: source, Line: line}:
+		s.urls++
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package webreader
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Filter selects URLs by regular expressions. A URL passes if it matches at least one include pattern,
+// or there are none, and matches no exclude pattern. The zero Filter passes every URL.
+type Filter struct {
+	include []*regexp.Regexp // Patterns of which a URL must match one, if any
+	exclude []*regexp.Regexp // Patterns a URL must not match
+}
+
+// NewFilter compiles include and exclude patterns into a Filter.
+//
+// Parameters:
+//   - include: Regular expressions (RE2 syntax) selecting URLs; empty to select every URL.
+//   - exclude: Regular expressions rejecting URLs.
+//
+// Returns:
+//   - The Filter, or an error if a pattern does not compile.
+func NewFilter(include, exclude []string) (Filter, error) {
+	var f Filter
+	for _, pattern := range include {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid include pattern: %w", err)
+		}
+		f.include = append(f.include, re)
+	}
+	for _, pattern := range exclude {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid exclude pattern: %w", err)
+		}
+		f.exclude = append(f.exclude, re)
+	}
+	return f, nil
+}
+
+// Match reports whether url passes the filter.
+func (f Filter) Match(url string) bool {
+	for _, re := range f.exclude {
+		if re.MatchString(url) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package webreader
+
+import "testing"
+
+func TestFilter_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		url      string
+		expected bool
+	}{
+		{name: "no patterns", url: "http://example.com/a", expected: true},
+		{name: "included", include: []string{`\.jar$`, `\.pom$`}, url: "http://example.com/a.pom", expected: true},
+		{name: "not included", include: []string{`\.jar$`}, url: "http://example.com/a.txt", expected: false},
+		{name: "excluded", exclude: []string{`/snapshots/`}, url: "http://example.com/snapshots/a.jar", expected: false},
+		{name: "exclude wins", include: []string{`\.jar$`}, exclude: []string{`-sources\.jar$`}, url: "http://example.com/a-sources.jar", expected: false},
+	}
+
+	for _, tt := range tests {
+		f, err := NewFilter(tt.include, tt.exclude)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got := f.Match(tt.url); got != tt.expected {
+			t.Errorf("%s: Match(%q) = %v, expected %v", tt.name, tt.url, got, tt.expected)
+		}
+	}
+}
+
+func TestNewFilter_Invalid(t *testing.T) {
+	if _, err := NewFilter([]string{"("}, nil); err == nil {
+		t.Error("expected an error for an invalid include pattern, got nil")
+	}
+	if _, err := NewFilter(nil, []string{"[a-"}); err == nil {
+		t.Error("expected an error for an invalid exclude pattern, got nil")
+	}
+}
@@ -0,0 +1,65 @@
+package webreader
+
+import (
+	"context"
+	"fmt"
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/pipeline"
+
+	"go.uber.org/zap"
+)
+
+// LinkReader is a pipeline.Stage emitting the targets of the links on HTML pages, such as directory listings.
+type LinkReader struct {
+	urls []string // Pages whose links are read
+	config
+}
+
+var _ pipeline.Stage[struct{}, models.URLRecord] = (*LinkReader)(nil)
+
+// NewLinkReader creates a new LinkReader instance reading the links of the given pages.
+//
+// Parameters:
+//   - urls: URLs of HTML pages.
+//   - opts: Optional settings.
+//
+// Returns:
+//   - A pointer to a new LinkReader instance.
+func NewLinkReader(urls []string, opts ...Option) *LinkReader {
+	return &LinkReader{urls: urls, config: newConfig(opts)}
+}
+
+// Execute fetches every page and sends the http and https targets of its <a> and <area> links to the
+// output channel. Each record carries the page and line the link was found on.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - input: Input channel (unused, LinkReader generates its own data).
+//   - output: Channel to send URL records to.
+//   - logger: Logger for logging progress and errors.
+//
+// Returns:
+//   - An error if a page cannot be fetched or parsed, nil otherwise.
+func (lr *LinkReader) Execute(ctx context.Context, input <-chan struct{}, output chan<- models.URLRecord, logger *zap.Logger) error {
+	sink := &recordSink{ctx: ctx, output: output, logger: logger, filter: lr.filter}
+	for _, pageURL := range lr.urls {
+		data, err := lr.fetch(ctx, pageURL)
+		if err != nil {
+			return fmt.Errorf("fetch page %s: %w", pageURL, err)
+		}
+		links, err := ExtractLinks(data, pageURL)
+		if err != nil {
+			return fmt.Errorf("parse page %s: %w", pageURL, err)
+		}
+		for _, link := range links {
+			if err := sink.emit(link.URL, pageURL, link.Line); err != nil {
+				return err
+			}
+		}
+	}
+	logger.Info("finished reading links",
+		zap.Int("pages", len(lr.urls)),
+		zap.Int("total_urls", sink.urls),
+		zap.Int("filtered_urls", sink.filtered))
+	return nil
+}
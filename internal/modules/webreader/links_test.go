@@ -0,0 +1,102 @@
+package webreader
+
+import (
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/downloader"
+	"jfrog-assignment/internal/modules/pipeline"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+func TestLinkReader(t *testing.T) {
+	ts := serve(t, map[string]string{
+		"/releases/": "<ul>\n<li><a href=\"v1.tar.gz\">v1</a></li>\n<li><a href=\"v1.tar.gz.asc\">sig</a></li>\n<li><a href=\"notes.html\">notes</a></li>\n</ul>",
+	})
+	filter, err := NewFilter([]string{`\.tar\.gz`}, []string{`\.asc$`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := collect(NewLinkReader([]string{ts.URL + "/releases/"}, WithClient(ts.Client()), WithFilter(filter)), zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 || records[0].URL != ts.URL+"/releases/v1.tar.gz" || records[0].Origin() != ts.URL+"/releases/:2" {
+		t.Errorf("unexpected records %+v", records)
+	}
+
+	if _, err := collect(NewLinkReader([]string{ts.URL + "/missing/"}, WithClient(ts.Client())), zaptest.NewLogger(t)); err == nil {
+		t.Error("expected an error for a missing page, got nil")
+	}
+}
+
+func TestReaders_Authenticated(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		switch {
+		case r.URL.Path == "/sitemap.xml" && r.Header.Get("X-Api-Key") == "secret":
+			w.Write([]byte(`<urlset><url><loc>/a.jar</loc></url></urlset>`))
+		case r.URL.Path == "/releases/" && user == "ci" && pass == "s3cret":
+			w.Write([]byte(`<a href="b.jar">b</a>`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+	hostname, _, _ := strings.Cut(host, ":")
+
+	netrcPath := filepath.Join(t.TempDir(), ".netrc")
+	if err := os.WriteFile(netrcPath, []byte("machine "+hostname+" login ci password s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	netrc, err := downloader.LoadNetrc(netrcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hd := downloader.New(
+		downloader.WithHeaders(downloader.RequestHeaders{Hosts: map[string]map[string]string{host: {"X-Api-Key": "secret"}}}),
+		downloader.WithNetrc(netrc))
+
+	tests := []struct {
+		name   string
+		reader func(opts ...Option) pipeline.Stage[struct{}, models.URLRecord]
+		want   string
+	}{
+		{
+			name: "sitemap with per-host header",
+			reader: func(opts ...Option) pipeline.Stage[struct{}, models.URLRecord] {
+				return NewSitemapReader([]string{ts.URL + "/sitemap.xml"}, opts...)
+			},
+			want: ts.URL + "/a.jar",
+		},
+		{
+			name: "link page with netrc credentials",
+			reader: func(opts ...Option) pipeline.Stage[struct{}, models.URLRecord] {
+				return NewLinkReader([]string{ts.URL + "/releases/"}, opts...)
+			},
+			want: ts.URL + "/releases/b.jar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := collect(tt.reader(), zaptest.NewLogger(t)); err == nil {
+				t.Error("expected the server to refuse a bare client, got nil")
+			}
+			records, err := collect(tt.reader(WithClient(hd)), zaptest.NewLogger(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(records) != 1 || records[0].URL != tt.want {
+				t.Errorf("expected %s, got %+v", tt.want, records)
+			}
+		})
+	}
+}
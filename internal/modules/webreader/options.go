@@ -0,0 +1,44 @@
+package webreader
+
+// Option configures a SitemapReader or a LinkReader.
+type Option func(*config)
+
+// WithClient sets the HTTP client documents are fetched with. http.DefaultClient is used by default.
+//
+// Parameters:
+//   - client: The client, e.g. one created with downloader.NewClient, or a downloader.HTTPDownloader to send
+//     its headers and netrc credentials along.
+//
+// Returns:
+//   - An Option setting the client.
+func WithClient(client Doer) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WithFilter sets the filter URLs found in documents must pass. Every URL passes by default.
+//
+// Parameters:
+//   - filter: The filter, e.g. one created with NewFilter.
+//
+// Returns:
+//   - An Option setting the filter.
+func WithFilter(filter Filter) Option {
+	return func(c *config) {
+		c.filter = filter
+	}
+}
+
+// WithMaxDocumentSize sets the largest document read, after decompression. DefaultMaxDocumentSize is used by default.
+//
+// Parameters:
+//   - size: The limit in bytes.
+//
+// Returns:
+//   - An Option setting the limit.
+func WithMaxDocumentSize(size int64) Option {
+	return func(c *config) {
+		c.maxSize = size
+	}
+}
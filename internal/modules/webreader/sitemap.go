@@ -0,0 +1,164 @@
+package webreader
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"jfrog-assignment/internal/models"
+	"jfrog-assignment/internal/modules/pipeline"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// maxSitemapDepth is how deeply sitemap indexes may refer to further indexes.
+const maxSitemapDepth = 5
+
+// SitemapReader is a pipeline.Stage emitting the URLs listed in XML sitemaps. Sitemap indexes are followed
+// to the sitemaps they list, and gzipped sitemaps are decompressed.
+type SitemapReader struct {
+	urls []string // Sitemaps or sitemap indexes to read
+	config
+}
+
+var _ pipeline.Stage[struct{}, models.URLRecord] = (*SitemapReader)(nil)
+
+// NewSitemapReader creates a new SitemapReader instance reading the given sitemaps.
+//
+// Parameters:
+//   - urls: URLs of sitemaps or sitemap indexes.
+//   - opts: Optional settings.
+//
+// Returns:
+//   - A pointer to a new SitemapReader instance.
+func NewSitemapReader(urls []string, opts ...Option) *SitemapReader {
+	return &SitemapReader{urls: urls, config: newConfig(opts)}
+}
+
+// Execute fetches every sitemap and sends the URLs it lists to the output channel. Each record carries
+// the sitemap and line it was listed on. Sitemaps listed by an index that cannot be read are logged and skipped.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - input: Input channel (unused, SitemapReader generates its own data).
+//   - output: Channel to send URL records to.
+//   - logger: Logger for logging progress and errors.
+//
+// Returns:
+//   - An error if one of the given sitemaps cannot be fetched or parsed, nil otherwise.
+func (sr *SitemapReader) Execute(ctx context.Context, input <-chan struct{}, output chan<- models.URLRecord, logger *zap.Logger) error {
+	sink := &recordSink{ctx: ctx, output: output, logger: logger, filter: sr.filter}
+	visited := make(map[string]bool)
+	for _, sitemapURL := range sr.urls {
+		if err := sr.read(ctx, sitemapURL, 0, visited, sink); err != nil {
+			return err
+		}
+	}
+	logger.Info("finished reading sitemaps",
+		zap.Int("sitemaps", len(visited)),
+		zap.Int("total_urls", sink.urls),
+		zap.Int("filtered_urls", sink.filtered))
+	return nil
+}
+
+// read fetches one sitemap or sitemap index and emits the URLs it lists, following nested sitemaps.
+func (sr *SitemapReader) read(ctx context.Context, sitemapURL string, depth int, visited map[string]bool, sink *recordSink) error {
+	if visited[sitemapURL] {
+		return nil
+	}
+	visited[sitemapURL] = true
+
+	data, err := sr.fetch(ctx, sitemapURL)
+	if err != nil {
+		return fmt.Errorf("fetch sitemap %s: %w", sitemapURL, err)
+	}
+	nested, err := parseSitemap(data, sitemapURL, sink)
+	if err != nil {
+		return fmt.Errorf("parse sitemap %s: %w", sitemapURL, err)
+	}
+
+	for _, child := range nested {
+		if depth+1 > maxSitemapDepth {
+			sink.logger.Warn("skipping sitemap nested too deeply", zap.String("url", child), zap.String("index", sitemapURL))
+			continue
+		}
+		if err := sr.read(ctx, child, depth+1, visited, sink); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			sink.logger.Warn("skipping sitemap", zap.String("index", sitemapURL), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// parseSitemap emits the page URLs of a <urlset> document, or returns the sitemap URLs of a <sitemapindex>.
+// Relative locations are resolved against the sitemap URL.
+func parseSitemap(data []byte, sitemapURL string, sink *recordSink) ([]string, error) {
+	base, err := url.Parse(sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var (
+		root   string   // Local name of the document element
+		stack  []string // Local names of the open elements
+		nested []string // Sitemaps listed by an index
+	)
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if root == "" {
+				root = t.Name.Local
+				if root != "urlset" && root != "sitemapindex" {
+					return nil, fmt.Errorf("unexpected document element <%s>, expected <urlset> or <sitemapindex>", root)
+				}
+			}
+			if t.Name.Local != "loc" || len(stack) != 2 {
+				stack = append(stack, t.Name.Local)
+				continue
+			}
+			parent := stack[1]
+
+			line, _ := dec.InputPos()
+			var loc string
+			if err := dec.DecodeElement(&loc, &t); err != nil {
+				return nil, err
+			}
+			loc = strings.TrimSpace(loc)
+			ref, err := base.Parse(loc)
+			if err != nil || loc == "" {
+				sink.logger.Warn("skipping invalid sitemap location",
+					zap.String("sitemap", sitemapURL), zap.Int("line", line), zap.String("loc", loc))
+				continue
+			}
+			switch {
+			case root == "urlset" && parent == "url":
+				if err := sink.emit(ref.String(), sitemapURL, line); err != nil {
+					return nil, err
+				}
+			case root == "sitemapindex" && parent == "sitemap":
+				nested = append(nested, ref.String())
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if root == "" {
+		return nil, fmt.Errorf("empty document")
+	}
+	return nested, nil
+}
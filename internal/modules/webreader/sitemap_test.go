@@ -0,0 +1,129 @@
+package webreader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"jfrog-assignment/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// serve starts a server answering each path with the given body, and 404 for any other path.
+func serve(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(strings.ReplaceAll(body, "{server}", "http://"+r.Host)))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// collect runs a reading stage and returns the records it sent.
+func collect(stage interface {
+	Execute(context.Context, <-chan struct{}, chan<- models.URLRecord, *zap.Logger) error
+}, logger *zap.Logger) ([]models.URLRecord, error) {
+	input := make(chan struct{})
+	close(input)
+	output := make(chan models.URLRecord, 100)
+	err := stage.Execute(context.Background(), input, output, logger)
+	close(output)
+
+	var records []models.URLRecord
+	for rec := range output {
+		records = append(records, rec)
+	}
+	return records, err
+}
+
+// gzipped compresses s.
+func gzipped(s string) string {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	gw.Write([]byte(s))
+	gw.Close()
+	return buf.String()
+}
+
+func TestSitemapReader(t *testing.T) {
+	ts := serve(t, map[string]string{
+		"/sitemap_index.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>{server}/pages.xml</loc></sitemap>
+  <sitemap><loc>/files.xml.gz</loc></sitemap>
+  <sitemap><loc>{server}/missing.xml</loc></sitemap>
+  <sitemap><loc>{server}/sitemap_index.xml</loc></sitemap>
+</sitemapindex>`,
+		"/pages.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>{server}/a.html</loc>
+    <lastmod>2025-01-01</lastmod>
+  </url>
+  <url><loc> {server}/b.html?x=1&amp;y=2 </loc></url>
+</urlset>`,
+		"/files.xml.gz": gzipped(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<url><loc>/app.jar</loc></url>
+<url><loc>/app-sources.jar</loc></url>
+</urlset>`),
+	})
+
+	filter, err := NewFilter(nil, []string{`-sources\.jar$`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := collect(NewSitemapReader([]string{ts.URL + "/sitemap_index.xml"}, WithClient(ts.Client()), WithFilter(filter)), zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []models.URLRecord{
+		{URL: ts.URL + "/a.html", Source: ts.URL + "/pages.xml", Line: 4},
+		{URL: ts.URL + "/b.html?x=1&y=2", Source: ts.URL + "/pages.xml", Line: 7},
+		{URL: ts.URL + "/app.jar", Source: ts.URL + "/files.xml.gz", Line: 2},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, got %+v", len(expected), records)
+	}
+	for i := range expected {
+		if records[i].URL != expected[i].URL || records[i].Origin() != expected[i].Origin() {
+			t.Errorf("record %d: expected %+v, got %+v", i, expected[i], records[i])
+		}
+	}
+}
+
+func TestSitemapReader_Errors(t *testing.T) {
+	ts := serve(t, map[string]string{
+		"/feed.xml":   `<rss><channel></channel></rss>`,
+		"/broken.xml": `<urlset><url><loc>http://example.com/a</loc>`,
+		"/empty.xml":  ``,
+	})
+
+	for _, path := range []string{"/missing.xml", "/feed.xml", "/broken.xml", "/empty.xml"} {
+		_, err := collect(NewSitemapReader([]string{ts.URL + path}, WithClient(ts.Client())), zaptest.NewLogger(t))
+		if err == nil {
+			t.Errorf("%s: expected an error, got nil", path)
+		}
+	}
+}
+
+func TestSitemapReader_MaxDocumentSize(t *testing.T) {
+	ts := serve(t, map[string]string{
+		"/sitemap.xml.gz": gzipped("<urlset>" + strings.Repeat("<url><loc>http://example.com/a</loc></url>", 100) + "</urlset>"),
+	})
+
+	_, err := collect(NewSitemapReader([]string{ts.URL + "/sitemap.xml.gz"}, WithClient(ts.Client()), WithMaxDocumentSize(1024)), zaptest.NewLogger(t))
+	if err == nil || !strings.Contains(err.Error(), "size limit") {
+		t.Errorf("expected a size limit error for the decompressed sitemap, got %v", err)
+	}
+}
@@ -49,21 +49,37 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	exitCode := make(chan int, 1)
 	go func() {
-		cmd.Execute(ctx, logger)
+		exitCode <- cmd.Execute(ctx, logger)
 		cancel()
 	}()
 
 	select {
-	case <-ctx.Done():
+	case code := <-exitCode:
 		logger.Info("main context done")
+		if code != 0 {
+			logger.Sync()
+			os.Exit(code)
+		}
 	case sig := <-sigChan:
 		logger.Info("received shutdown signal", zap.String("signal", sig.String()))
 		cancel()
 
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer shutdownCancel()
-		<-shutdownCtx.Done()
-		logger.Info("shutdown completed")
+		// Wait for Execute to save the manifest, cookie jar and CAS index, but not forever.
+		timer := time.NewTimer(5 * time.Second)
+		defer timer.Stop()
+		select {
+		case code := <-exitCode:
+			logger.Info("shutdown completed")
+			if code != 0 {
+				logger.Sync()
+				os.Exit(code)
+			}
+		case <-timer.C:
+			logger.Warn("shutdown timed out")
+			logger.Sync()
+			os.Exit(1)
+		}
 	}
 }